@@ -0,0 +1,229 @@
+package main
+
+import (
+	"bufio"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// AdjacencyListEdgeSource reads an adjacency list with one vertex and its neighbours per line. A
+// METIS file instead has a header giving the number of vertices and lists the neighbours of vertex
+// i on the i-th line, read as the arcs from each vertex to its neighbours if the edges are directed.
+type AdjacencyListEdgeSource struct {
+	filepath           string
+	neighbourSeparator string
+	metisHeader        bool
+	directed           bool
+}
+
+// splitNeighbours splits a list of neighbours, with a blank or space separator splitting on any
+// run of whitespace and empty neighbours dropped
+func splitNeighbours(neighbours string, separator string) []string {
+
+	if len(strings.TrimSpace(separator)) == 0 {
+		return strings.Fields(neighbours)
+	}
+
+	fields := []string{}
+	for _, field := range strings.Split(neighbours, separator) {
+		if field = strings.TrimSpace(field); len(field) > 0 {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
+
+// ReadEdges reads the adjacency list
+func (s AdjacencyListEdgeSource) ReadEdges(handlers EdgeHandlers) {
+
+	// Open the file for reading and ensure it is closed
+	file, err := os.Open(s.filepath)
+	if err != nil {
+		log.Fatal("[!] Couldn't open adjacency list file ", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 256*1024*1024)
+
+	if s.metisHeader {
+		readMETISEdges(scanner, s.directed, handlers)
+	} else {
+		readAdjacencyListEdges(scanner, s.neighbourSeparator, handlers)
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Fatal("[!] Error reading adjacency list file: ", err)
+	}
+}
+
+// readAdjacencyListEdges reads lines of a vertex followed by its neighbours, as in the SNAP format,
+// passing each vertex as a vertex and an edge to each neighbour. The vertex is separated from its
+// neighbours by a tab, or by the neighbour separator if the line has no tab. Lines starting with #
+// are comments. Each line is read as the directed half-edges out of its vertex, so an undirected
+// edge listed under both of its vertices is passed twice, once in each direction.
+func readAdjacencyListEdges(scanner *bufio.Scanner, neighbourSeparator string, handlers EdgeHandlers) {
+
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+
+		// Skip blank lines and comments
+		if len(strings.TrimSpace(line)) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var vertex string
+		var neighbours []string
+
+		if tab := strings.IndexByte(line, '\t'); tab >= 0 {
+			vertex = strings.TrimSpace(line[:tab])
+			neighbours = splitNeighbours(line[tab+1:], neighbourSeparator)
+		} else {
+			fields := splitNeighbours(line, neighbourSeparator)
+			if len(fields) == 0 {
+				handlers.ParseError(lineNumber, line, "missing vertex")
+				continue
+			}
+			vertex = fields[0]
+			neighbours = fields[1:]
+		}
+
+		handlers.Vertex(lineNumber, vertex)
+
+		for _, neighbour := range neighbours {
+			handlers.Edge(lineNumber, []string{vertex, neighbour}, "")
+		}
+	}
+}
+
+// readMETISEdges reads a METIS graph file, passing vertices 1 to n as vertices and each edge once.
+// METIS lists every edge under both of its vertices, so only the edge from the lower numbered
+// vertex to the higher is passed, unless the edges are directed, when each neighbour listed is an
+// arc from the vertex and every arc is passed. Vertex sizes and vertex weights given by the format
+// in the header are skipped, and edge weights are passed with the edges. Lines starting with % are
+// comments.
+func readMETISEdges(scanner *bufio.Scanner, directed bool, handlers EdgeHandlers) {
+
+	lineNumber := 0
+	numberVertices := -1
+	vertex := 0
+
+	// Number of values before the neighbours and after each neighbour
+	numberVertexValues := 0
+	numberEdgeValues := 0
+
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+
+		// Skip comments, but not blank lines which are vertices without neighbours
+		if strings.HasPrefix(line, "%") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+
+		// The header gives the number of vertices and edges, the format and the number of vertex weights
+		if numberVertices < 0 {
+			if len(fields) == 0 {
+				continue
+			}
+
+			var err error
+			numberVertices, err = strconv.Atoi(fields[0])
+			if err != nil || numberVertices < 0 || len(fields) < 2 {
+				log.Fatalf("[!] Error parsing METIS file: invalid header at line %v\n", lineNumber)
+			}
+
+			if len(fields) >= 3 {
+				format := fields[2]
+				for len(format) < 3 {
+					format = "0" + format
+				}
+				numberWeights := 1
+				if len(fields) >= 4 {
+					if numberWeights, err = strconv.Atoi(fields[3]); err != nil {
+						log.Fatalf("[!] Error parsing METIS file: invalid number of vertex weights at line %v\n", lineNumber)
+					}
+				}
+				if format[0] == '1' {
+					numberVertexValues++
+				}
+				if format[1] == '1' {
+					numberVertexValues += numberWeights
+				}
+				if format[2] == '1' {
+					numberEdgeValues = 1
+				}
+			}
+
+			for i := 1; i <= numberVertices; i++ {
+				handlers.Vertex(lineNumber, strconv.Itoa(i))
+			}
+			continue
+		}
+
+		vertex++
+		if vertex > numberVertices {
+			if len(fields) > 0 {
+				handlers.ParseError(lineNumber, line, "more vertex lines than given in the header")
+			}
+			continue
+		}
+
+		if len(fields) < numberVertexValues {
+			handlers.ParseError(lineNumber, line, "missing vertex sizes or weights")
+			continue
+		}
+
+		// The whole line is checked before any of its edges are passed, so that a malformed line is
+		// rejected once rather than partly read
+		neighbours, reason := parseMETISNeighbours(fields[numberVertexValues:], numberEdgeValues, numberVertices)
+		if len(reason) > 0 {
+			handlers.ParseError(lineNumber, line, reason)
+			continue
+		}
+
+		source := strconv.Itoa(vertex)
+		for i, neighbour := range neighbours {
+
+			// An undirected edge is passed when it is listed under its lower numbered vertex
+			if !directed && neighbour < vertex {
+				continue
+			}
+
+			weight := ""
+			if numberEdgeValues > 0 {
+				weight = fields[numberVertexValues+i*(1+numberEdgeValues)+1]
+			}
+
+			handlers.Edge(lineNumber, []string{source, strconv.Itoa(neighbour)}, weight)
+		}
+	}
+}
+
+// parseMETISNeighbours parses the neighbours of a METIS vertex line, each followed by its edge
+// values, returning the neighbours or the reason the line is invalid
+func parseMETISNeighbours(fields []string, numberEdgeValues int, numberVertices int) ([]int, string) {
+
+	if len(fields)%(1+numberEdgeValues) != 0 {
+		return nil, "missing edge weight"
+	}
+
+	neighbours := make([]int, 0, len(fields)/(1+numberEdgeValues))
+	for i := 0; i < len(fields); i += 1 + numberEdgeValues {
+		neighbour, err := strconv.Atoi(fields[i])
+		if err != nil || neighbour < 1 || neighbour > numberVertices {
+			return nil, "neighbour out of range: " + fields[i]
+		}
+		neighbours = append(neighbours, neighbour)
+	}
+
+	return neighbours, ""
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitNeighbours(t *testing.T) {
+	neighbours := map[string][]string{
+		" ": []string{"a", "b,", "c"},
+		"":  []string{"a", "b,", "c"},
+		",": []string{"a b", "c"},
+	}
+
+	for separator, expected := range neighbours {
+		if actual := splitNeighbours(" a b,\t c ", separator); !reflect.DeepEqual(expected, actual) {
+			t.Fatalf("Expected %v with separator %q, got %v\n", expected, separator, actual)
+		}
+	}
+}
+
+func TestAdjacencyListEdgeSourceSeparator(t *testing.T) {
+
+	edges, vertices, _ := readSource(AdjacencyListEdgeSource{filepath: "./test/neighbours.adj", neighbourSeparator: ","})
+
+	expectedEdges := [][]string{{"a", "b"}, {"a", "c"}, {"a", "d"}, {"e", "f"}}
+
+	if !reflect.DeepEqual(expectedEdges, edges) {
+		t.Fatalf("Expected %v, got %v\n", expectedEdges, edges)
+	}
+
+	expectedVertices := []string{"a", "e", "g"}
+
+	if !reflect.DeepEqual(expectedVertices, vertices) {
+		t.Fatalf("Expected %v, got %v\n", expectedVertices, vertices)
+	}
+}
+
+func TestAdjacencyListEdgeSourceMETISWeights(t *testing.T) {
+
+	edges, vertices, parseErrors := readSource(AdjacencyListEdgeSource{filepath: "./test/weights.metis", metisHeader: true})
+
+	// Vertex sizes and vertex weights are skipped, and each edge listed under both of its vertices
+	// is passed once with its weight
+	expectedEdges := [][]string{{"1", "2", "7"}, {"2", "3", "2"}}
+
+	if !reflect.DeepEqual(expectedEdges, edges) {
+		t.Fatalf("Expected %v, got %v\n", expectedEdges, edges)
+	}
+
+	if !reflect.DeepEqual([]string{"1", "2", "3"}, vertices) {
+		t.Fatalf("Expected vertices 1 to 3, got %v\n", vertices)
+	}
+
+	if len(parseErrors) != 0 {
+		t.Fatalf("Expected no parse errors, got %v\n", parseErrors)
+	}
+
+	// Directed, each neighbour listed is an arc from its vertex
+	edges, _, _ = readSource(AdjacencyListEdgeSource{filepath: "./test/weights.metis", metisHeader: true, directed: true})
+	expectedArcs := [][]string{{"1", "2", "7"}, {"2", "1", "7"}, {"2", "3", "2"}, {"3", "2", "2"}}
+
+	if !reflect.DeepEqual(expectedArcs, edges) {
+		t.Fatalf("Expected %v, got %v\n", expectedArcs, edges)
+	}
+}
+
+func TestAdjacencyListEdgeSourceMETISMalformedLines(t *testing.T) {
+
+	edges, _, parseErrors := readSource(AdjacencyListEdgeSource{filepath: "./test/malformed.metis", metisHeader: true})
+
+	// A line with any invalid neighbour or a missing edge weight is rejected once, and none of its
+	// edges are passed
+	expectedEdges := [][]string{{"1", "2", "7"}, {"1", "3", "1"}}
+
+	if !reflect.DeepEqual(expectedEdges, edges) {
+		t.Fatalf("Expected %v, got %v\n", expectedEdges, edges)
+	}
+
+	if !reflect.DeepEqual([]int{4, 5, 6}, parseErrors) {
+		t.Fatalf("Expected parse errors on lines 4, 5 and 6, got %v\n", parseErrors)
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"io"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+)
+
+// arrowFileMagic starts an Arrow IPC file, as opposed to an Arrow IPC stream
+const arrowFileMagic = "ARROW1"
+
+// arrowColumnIndex returns the index of a named column in a record batch, or a default index if the
+// name is blank
+func arrowColumnIndex(record arrow.RecordBatch, name string, defaultIndex int) int {
+
+	if len(name) == 0 {
+		if defaultIndex < int(record.NumCols()) {
+			return defaultIndex
+		}
+		log.Fatalf("[!] Arrow input has fewer than %v columns\n", defaultIndex+1)
+	}
+
+	indices := record.Schema().FieldIndices(name)
+	if len(indices) == 0 {
+		log.Fatalf("[!] Column %q not found in Arrow input\n", name)
+	}
+
+	return indices[0]
+}
+
+// arrowValueString returns a value of a string, binary, integer or floating point column as a
+// string, with nulls given as blank strings
+func arrowValueString(column arrow.Array, i int) string {
+
+	if column.IsNull(i) {
+		return ""
+	}
+
+	switch c := column.(type) {
+	case *array.String:
+		return c.Value(i)
+	case *array.LargeString:
+		return c.Value(i)
+	case *array.Binary:
+		return string(c.Value(i))
+	case *array.LargeBinary:
+		return string(c.Value(i))
+	case *array.Int8:
+		return strconv.FormatInt(int64(c.Value(i)), 10)
+	case *array.Int16:
+		return strconv.FormatInt(int64(c.Value(i)), 10)
+	case *array.Int32:
+		return strconv.FormatInt(int64(c.Value(i)), 10)
+	case *array.Int64:
+		return strconv.FormatInt(c.Value(i), 10)
+	case *array.Uint8:
+		return strconv.FormatUint(uint64(c.Value(i)), 10)
+	case *array.Uint16:
+		return strconv.FormatUint(uint64(c.Value(i)), 10)
+	case *array.Uint32:
+		return strconv.FormatUint(uint64(c.Value(i)), 10)
+	case *array.Uint64:
+		return strconv.FormatUint(c.Value(i), 10)
+	case *array.Float32:
+		return strconv.FormatFloat(float64(c.Value(i)), 'g', -1, 32)
+	case *array.Float64:
+		return strconv.FormatFloat(c.Value(i), 'g', -1, 64)
+	}
+
+	log.Fatalf("[!] Unsupported Arrow column type %v\n", column.DataType())
+	return ""
+}
+
+// readArrowEdges reads the source and target columns of an Arrow IPC file or stream, a record batch
+// at a time, passing each row with its row number and the value of the weight column, if one is
+// named, to the row handler. Blank source and target column names select the first and second
+// columns.
+func readArrowEdges(filepath string, sourceColumn string, targetColumn string, weightColumn string,
+	handleRow func(int, []string, string)) {
+
+	file, err := os.Open(filepath)
+	if err != nil {
+		log.Fatal("[!] Couldn't open Arrow file ", err)
+	}
+	defer file.Close()
+
+	// An Arrow IPC file starts with magic bytes, otherwise the input is read as a stream
+	magic := make([]byte, len(arrowFileMagic))
+	_, err = io.ReadFull(file, magic)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		log.Fatal("[!] Couldn't read Arrow file ", err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		log.Fatal("[!] Couldn't read Arrow file ", err)
+	}
+
+	// nextRecord returns the next record batch, or nil at the end of the input
+	var nextRecord func() arrow.RecordBatch
+
+	if string(magic) == arrowFileMagic {
+		reader, err := ipc.NewFileReader(file)
+		if err != nil {
+			log.Fatal("[!] Couldn't read Arrow file ", err)
+		}
+		defer reader.Close()
+
+		nextRecord = func() arrow.RecordBatch {
+			record, err := reader.Read()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				log.Fatal("[!] Couldn't read Arrow record batch ", err)
+			}
+			return record
+		}
+	} else {
+		reader, err := ipc.NewReader(file)
+		if err != nil {
+			log.Fatal("[!] Couldn't read Arrow stream ", err)
+		}
+		defer reader.Release()
+
+		nextRecord = func() arrow.RecordBatch {
+			if !reader.Next() {
+				if err := reader.Err(); err != nil && err != io.EOF {
+					log.Fatal("[!] Couldn't read Arrow record batch ", err)
+				}
+				return nil
+			}
+			return reader.RecordBatch()
+		}
+	}
+
+	rowNumber := 0
+	for record := nextRecord(); record != nil; record = nextRecord() {
+
+		source := record.Column(arrowColumnIndex(record, sourceColumn, 0))
+		target := record.Column(arrowColumnIndex(record, targetColumn, 1))
+		var weights arrow.Array
+		if len(weightColumn) > 0 {
+			weights = record.Column(arrowColumnIndex(record, weightColumn, 2))
+		}
+
+		for i := 0; i < int(record.NumRows()); i++ {
+			rowNumber++
+			weight := ""
+			if weights != nil {
+				weight = arrowValueString(weights, i)
+			}
+			handleRow(rowNumber, []string{arrowValueString(source, i), arrowValueString(target, i)}, weight)
+		}
+	}
+}
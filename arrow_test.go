@@ -0,0 +1,98 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// writeArrowEdges writes an edge list with an integer source and a string target column, in two
+// record batches, as an Arrow IPC file or stream
+func writeArrowEdges(t *testing.T, filepath string, stream bool) {
+
+	file, err := os.Create(filepath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "from", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "to", Type: arrow.BinaryTypes.String, Nullable: true},
+	}, nil)
+
+	builder := array.NewRecordBuilder(memory.NewGoAllocator(), schema)
+	defer builder.Release()
+
+	batches := []arrow.RecordBatch{}
+	builder.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 3}, nil)
+	builder.Field(1).(*array.StringBuilder).AppendValues([]string{"2", "4"}, nil)
+	batches = append(batches, builder.NewRecordBatch())
+	builder.Field(0).(*array.Int64Builder).AppendValues([]int64{6, 5}, nil)
+	builder.Field(1).(*array.StringBuilder).Append("3")
+	builder.Field(1).(*array.StringBuilder).AppendNull()
+	batches = append(batches, builder.NewRecordBatch())
+
+	if stream {
+		writer := ipc.NewWriter(file, ipc.WithSchema(schema))
+		for _, batch := range batches {
+			if err := writer.Write(batch); err != nil {
+				t.Fatal(err)
+			}
+		}
+		writer.Close()
+	} else {
+		writer, err := ipc.NewFileWriter(file, ipc.WithSchema(schema))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, batch := range batches {
+			if err := writer.Write(batch); err != nil {
+				t.Fatal(err)
+			}
+		}
+		writer.Close()
+	}
+
+	for _, batch := range batches {
+		batch.Release()
+	}
+}
+
+func TestConnectedComponentsFromArrow(t *testing.T) {
+
+	writeArrowEdges(t, "./test/edges-actual.arrow", false)
+	writeArrowEdges(t, "./test/edges-actual.arrows", true)
+
+	for _, filepath := range []string{"./test/edges-actual.arrow", "./test/edges-actual.arrows"} {
+
+		// The null target in the last row is skipped as a blank ID
+		options := ReadOptions{
+			SourceColumn: "from",
+			TargetColumn: "to",
+			ErrorHandler: NewRowErrorHandler(ErrorModeSkip, 0, ""),
+		}
+		stats, cc := connectedComponentsFromFile(filepath, options)
+
+		if stats.RowsRead != 4 || stats.SkippedRows != 1 {
+			t.Fatalf("Expected 4 rows read and 1 skipped from %v, got %+v\n", filepath, *stats)
+		}
+
+		expectedVertexToComponent := map[string]int{
+			"1": 0,
+			"2": 0,
+			"3": 1,
+			"4": 1,
+			"6": 1,
+		}
+
+		if !reflect.DeepEqual(expectedVertexToComponent, cc.vertexToConnectedComponent) {
+			t.Fatalf("Expected %v from %v, got %v\n", expectedVertexToComponent, filepath, cc.vertexToConnectedComponent)
+		}
+	}
+}
@@ -1,14 +1,16 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/csv"
 	"flag"
-	"fmt"
 	"io"
 	"log"
 	"os"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -22,8 +24,10 @@ type EntityPair struct {
 type ConnectedComponents struct {
 	vertexToConnectedComponent   map[string]int
 	connectedComponentToVertices map[int][]string
+	connectedComponentToEdges    map[int]int
 	nextConnectedComponentID     int
 	numberConnectedComponents    int
+	vertexToDegree               map[string]int
 }
 
 // NewConnectedComponents sets up a new ConnectedComponents struct
@@ -31,6 +35,7 @@ func NewConnectedComponents() ConnectedComponents {
 	return ConnectedComponents{
 		vertexToConnectedComponent:   map[string]int{},
 		connectedComponentToVertices: map[int][]string{},
+		connectedComponentToEdges:    map[int]int{},
 		nextConnectedComponentID:     0,
 		numberConnectedComponents:    0,
 	}
@@ -44,19 +49,31 @@ func minMax(v1 int, v2 int) (int, int) {
 	return v2, v1
 }
 
-// AddEdge adds an edge to the graph and causes the connected components to be updated
-func (c *ConnectedComponents) AddEdge(pair EntityPair) {
+// AddEdge adds an edge to the graph and causes the connected components to be updated; it
+// returns the number of vertices not seen before and whether two components were merged
+func (c *ConnectedComponents) AddEdge(pair EntityPair) (int, bool) {
 
 	// Connected component IDs given the vertex IDs
 	cc1, present1 := c.vertexToConnectedComponent[pair.EntityID1]
 	cc2, present2 := c.vertexToConnectedComponent[pair.EntityID2]
 
-	if present1 && present2 {
+	if pair.EntityID1 == pair.EntityID2 {
+		// A self-loop only adds the vertex, if it hasn't been seen before
+		added := c.AddVertex(pair.EntityID1)
+		c.connectedComponentToEdges[c.vertexToConnectedComponent[pair.EntityID1]]++
+
+		if added {
+			return 1, false
+		}
+		return 0, false
+
+	} else if present1 && present2 {
 		// Both vertices have been seen before
 
 		if cc1 == cc2 {
 			// Both vertices already belong to the same connected component
-			return
+			c.connectedComponentToEdges[cc1]++
+			return 0, false
 		}
 
 		// Lowest and highest connected components numbers
@@ -70,21 +87,33 @@ func (c *ConnectedComponents) AddEdge(pair EntityPair) {
 			c.connectedComponentToVertices[lowestCC] = append(c.connectedComponentToVertices[lowestCC], vertex)
 		}
 
+		// Combine the edges of both components, including the merging edge
+		c.connectedComponentToEdges[lowestCC] += c.connectedComponentToEdges[highestCC] + 1
+
 		// Delete the now unused connected component
 		delete(c.connectedComponentToVertices, highestCC)
+		delete(c.connectedComponentToEdges, highestCC)
 
 		// There is now one fewer connected components due to the merge
 		c.numberConnectedComponents--
 
+		return 0, true
+
 	} else if !present1 && present2 {
 		// Only EntityID2 has been seen before
 		c.vertexToConnectedComponent[pair.EntityID1] = cc2
 		c.connectedComponentToVertices[cc2] = append(c.connectedComponentToVertices[cc2], pair.EntityID1)
+		c.connectedComponentToEdges[cc2]++
+
+		return 1, false
 
 	} else if present1 && !present2 {
 		// Only EntityID1 has been seen before
 		c.vertexToConnectedComponent[pair.EntityID2] = cc1
 		c.connectedComponentToVertices[cc1] = append(c.connectedComponentToVertices[cc1], pair.EntityID2)
+		c.connectedComponentToEdges[cc1]++
+
+		return 1, false
 
 	} else {
 		// Neither entity has been seen before, so add it to the same new connected component
@@ -92,16 +121,55 @@ func (c *ConnectedComponents) AddEdge(pair EntityPair) {
 		c.vertexToConnectedComponent[pair.EntityID2] = c.nextConnectedComponentID
 
 		c.connectedComponentToVertices[c.nextConnectedComponentID] = []string{pair.EntityID1, pair.EntityID2}
+		c.connectedComponentToEdges[c.nextConnectedComponentID] = 1
 
 		c.nextConnectedComponentID++
 		c.numberConnectedComponents++
+
+		return 2, false
 	}
 }
 
-// connectedComponentsFromFile determines the connected components from a file
-func connectedComponentsFromFile(filepath string) (int, *ConnectedComponents) {
+// AddVertex adds a vertex to the graph as a new single-vertex connected component if it hasn't
+// been seen before and returns true if the vertex was added
+func (c *ConnectedComponents) AddVertex(entityID string) bool {
+
+	if _, present := c.vertexToConnectedComponent[entityID]; present {
+		return false
+	}
+
+	c.vertexToConnectedComponent[entityID] = c.nextConnectedComponentID
+	c.connectedComponentToVertices[c.nextConnectedComponentID] = []string{entityID}
 
-	log.Printf("Reading graph from edge list file: %v\n", filepath)
+	c.nextConnectedComponentID++
+	c.numberConnectedComponents++
+
+	return true
+}
+
+// ReadOptions holds the optional behaviour when reading the input files
+type ReadOptions struct {
+	InputFormat          string
+	SourceColumn         string
+	TargetColumn         string
+	WeightColumn         string
+	Query                string
+	Directed             bool
+	NeighbourSeparator   string
+	Normaliser           *Normaliser
+	ErrorHandler         *RowErrorHandler
+	CountDuplicates      bool
+	TrackDegrees         bool
+	CollapseDuplicates   bool
+	DuplicateDetection   string
+	BloomCapacity        int
+	DeduplicatedFilepath string
+	Workers              int
+}
+
+// readCSVEdges reads the rows of a CSV edge list, passing each row with its line number to the row
+// handler and each row that can't be parsed to the parse error handler
+func readCSVEdges(filepath string, handleRow func(int, []string), handleParseError func(int, string, string)) {
 
 	// Open the file for reading and ensure it is closed
 	file, err := os.Open(filepath)
@@ -110,45 +178,407 @@ func connectedComponentsFromFile(filepath string) (int, *ConnectedComponents) {
 	}
 	defer file.Close()
 
-	// Instantiate the connected components data structure
-	cc := NewConnectedComponents()
+	parseCSVRows(file, 0, handleRow, handleParseError)
+}
 
-	// Parse the input file
-	r := csv.NewReader(file)
-	numRowsRead := 0
+// csvLineRecorder passes on the text read by a CSV reader, holding the lines of the row being read
+// and the lines read ahead of it, so that a row that can't be parsed can be quarantined as it was
+// written
+type csvLineRecorder struct {
+	input     io.Reader
+	firstLine int
+	lines     [][]byte
+	complete  bool
+}
+
+// Read reads from the input, adding the text to the lines held
+func (l *csvLineRecorder) Read(p []byte) (int, error) {
+
+	n, err := l.input.Read(p)
+
+	for data := p[:n]; len(data) > 0; {
+		end := bytes.IndexByte(data, '\n') + 1
+		if end == 0 {
+			end = len(data)
+		}
+
+		if len(l.lines) == 0 || l.complete {
+			l.lines = append(l.lines, nil)
+		}
+		last := len(l.lines) - 1
+		l.lines[last] = append(l.lines[last], data[:end]...)
+		l.complete = data[end-1] == '\n'
+
+		data = data[end:]
+	}
+
+	return n, err
+}
+
+// text returns the text of the lines from the start line to the end line, without the final line
+// break
+func (l *csvLineRecorder) text(startLine int, endLine int) string {
+
+	start := max(startLine-l.firstLine, 0)
+	end := min(endLine-l.firstLine+1, len(l.lines))
+	if start >= end {
+		return ""
+	}
+
+	text := string(bytes.Join(l.lines[start:end], nil))
+	return strings.TrimSuffix(strings.TrimSuffix(text, "\n"), "\r")
+}
+
+// discardBefore drops the lines before a line, which has been read
+func (l *csvLineRecorder) discardBefore(line int) {
+
+	drop := min(line-l.firstLine, len(l.lines))
+	if drop <= 0 {
+		return
+	}
+
+	kept := copy(l.lines, l.lines[drop:])
+	clear(l.lines[kept:])
+	l.lines = l.lines[:kept]
+	l.firstLine += drop
+}
+
+// parseCSVRows parses the rows of CSV text, passing each row with its line number to the row
+// handler and each row that can't be parsed with its text to the parse error handler, with the
+// line numbers offset by the number of lines before the text
+func parseCSVRows(input io.Reader, lineOffset int, handleRow func(int, []string), handleParseError func(int, string, string)) {
+
+	// Parse the input, allowing rows with the wrong number of fields so they can be handled
+	recorder := &csvLineRecorder{input: input, firstLine: 1}
+	r := csv.NewReader(recorder)
+	r.FieldsPerRecord = -1
 
 	for {
 		// Read a row from the file
 		row, err := r.Read()
-		numRowsRead++
-
-		if numRowsRead%1000000 == 0 {
-			log.Printf("Read %v rows\n", numRowsRead)
-		}
 
 		if err == io.EOF {
 			break
 		}
 
+		if parseErr, ok := err.(*csv.ParseError); ok {
+			handleParseError(lineOffset+parseErr.StartLine, recorder.text(parseErr.StartLine, parseErr.Line), parseErr.Err.Error())
+			recorder.discardBefore(parseErr.Line + 1)
+			continue
+		}
+
 		if err != nil {
 			log.Fatal("[!] Error reading CSV file: ", err)
 		}
 
-		if len(row) != 2 {
-			log.Fatal("[!] Invalid row: ", row)
+		lineNumber, _ := r.FieldPos(0)
+		recorder.discardBefore(lineNumber)
+		handleRow(lineOffset+lineNumber, row)
+	}
+}
+
+// connectedComponentsFromFile determines the connected components from a CSV, Parquet or Arrow IPC
+// edge list file, a graph file or a SQLite query, normalising the entity IDs if a normaliser is
+// given and passing invalid rows to the error handler
+func connectedComponentsFromFile(filepath string, options ReadOptions) (*RunStats, *ConnectedComponents) {
+
+	format := inputFormat(filepath, options.InputFormat)
+	log.Printf("Reading graph from %v edge list file: %v\n", format, filepath)
+
+	// Instantiate the connected components data structure
+	cc := NewConnectedComponents()
+
+	// Set up the run statistics and the set of edges seen, which is needed to detect, count or
+	// collapse duplicates or to write the deduplicated edge list, exact unless a Bloom filter is
+	// chosen
+	collapseDuplicates := options.TrackDegrees && options.CollapseDuplicates
+	duplicateDetection := options.DuplicateDetection
+	if len(duplicateDetection) == 0 &&
+		(options.CountDuplicates || collapseDuplicates || len(options.DeduplicatedFilepath) > 0) {
+		duplicateDetection = "exact"
+	}
+	stats := RunStats{DuplicateEdgesCounted: len(duplicateDetection) > 0}
+	edgeSet := newEdgeDeduplicator(duplicateDetection, options.Directed, options.BloomCapacity)
+
+	// The first occurrence of each edge is written to the deduplicated edge list as it is read
+	var deduplicatedWriter *csv.Writer
+	if len(options.DeduplicatedFilepath) > 0 {
+		deduplicatedFile, err := os.Create(options.DeduplicatedFilepath)
+		if err != nil {
+			log.Fatalf("[!] Unable to open deduplicated edge list file %v for writing: %v\n", options.DeduplicatedFilepath, err)
+		}
+		defer deduplicatedFile.Close()
+
+		deduplicatedWriter = csv.NewWriter(deduplicatedFile)
+		defer deduplicatedWriter.Flush()
+	}
+
+	// The degree of each vertex is counted as the edges are read, with each end of a self-loop
+	// counted, and with each distinct edge counted once if duplicates are collapsed
+	var degrees map[string]int
+	if options.TrackDegrees {
+		degrees = map[string]int{}
+	}
+
+	// Directed edges are held in a graph whose strongly connected components are found once all
+	// the edges have been read
+	var graph *DirectedGraph
+	if options.Directed {
+		graph = NewDirectedGraph()
+	}
+
+	// countRow counts a row read from the file, whether or not it is valid
+	countRow := func() {
+		stats.RowsRead++
+
+		if stats.RowsRead%1000000 == 0 {
+			log.Printf("Read %v rows\n", stats.RowsRead)
+		}
+	}
+
+	// handleParseError skips a row that couldn't be parsed
+	handleParseError := func(lineNumber int, text string, reason string) {
+		countRow()
+		options.ErrorHandler.Handle(lineNumber, []string{text}, reason)
+		stats.SkippedRows++
+	}
+
+	// handleRow validates a row and adds its edge to the graph
+	handleRow := func(lineNumber int, row []string, weight string) {
+		countRow()
+
+		if reason := validateRow(row); len(reason) > 0 {
+			options.ErrorHandler.Handle(lineNumber, row, reason)
+			stats.SkippedRows++
+			return
+		}
+
+		if _, reason := parseEdgeWeight(weight); len(reason) > 0 {
+			options.ErrorHandler.Handle(lineNumber, row, reason)
+			stats.SkippedRows++
+			return
 		}
 
 		entityPair := EntityPair{
-			EntityID1: row[0],
-			EntityID2: row[1],
+			EntityID1: options.Normaliser.Normalise(row[0]),
+			EntityID2: options.Normaliser.Normalise(row[1]),
+		}
+
+		if len(entityPair.EntityID1) == 0 || len(entityPair.EntityID2) == 0 {
+			options.ErrorHandler.Handle(lineNumber, row, "blank entity ID after normalisation")
+			stats.SkippedRows++
+			return
+		}
+
+		duplicate, reversed := false, false
+		if edgeSet != nil {
+			duplicate, reversed = edgeSet.AddWithDirection(entityPair)
+		}
+		if duplicate {
+			stats.DuplicateEdges++
+		}
+		if reversed {
+			stats.ReversedEdges++
+		}
+
+		if deduplicatedWriter != nil && !duplicate {
+			deduplicatedWriter.Write([]string{entityPair.EntityID1, entityPair.EntityID2})
 		}
 
-		cc.AddEdge(entityPair)
+		if degrees != nil && !(duplicate && collapseDuplicates) {
+			degrees[entityPair.EntityID1]++
+			degrees[entityPair.EntityID2]++
+		}
+
+		if graph != nil {
+			numberVertices := len(graph.vertices)
+			graph.AddEdge(entityPair)
+			stats.recordEdge(entityPair, len(graph.vertices)-numberVertices, false)
+			return
+		}
+
+		newVertices, merged := cc.AddEdge(entityPair)
+		stats.recordEdge(entityPair, newVertices, merged)
+	}
+
+	// declaredVertices holds the vertices declared by the input, which are added once the edges
+	// have been read so that they don't change the order in which components are numbered
+	declaredVertices := []string{}
+
+	// handleVertex validates a declared vertex and holds it until the edges have been read
+	handleVertex := func(lineNumber int, entityID string) {
+		countRow()
+
+		if reason := validateEntityID(entityID); len(reason) > 0 {
+			options.ErrorHandler.Handle(lineNumber, []string{entityID}, reason)
+			stats.SkippedRows++
+			return
+		}
+
+		normalisedID := options.Normaliser.Normalise(entityID)
+		if len(normalisedID) == 0 {
+			options.ErrorHandler.Handle(lineNumber, []string{entityID}, "blank entity ID after normalisation")
+			stats.SkippedRows++
+			return
+		}
+
+		declaredVertices = append(declaredVertices, normalisedID)
+	}
+
+	source := newEdgeSource(filepath, format, options)
+	source.ReadEdges(EdgeHandlers{Edge: handleRow, Vertex: handleVertex, ParseError: handleParseError})
+
+	log.Printf("Read %v rows from file %v\n", stats.RowsRead, filepath)
+
+	if len(declaredVertices) > 0 {
+		numberVerticesAdded := 0
+		for _, entityID := range declaredVertices {
+			if graph != nil {
+				numberVertices := len(graph.vertices)
+				graph.AddVertex(entityID)
+				numberVerticesAdded += len(graph.vertices) - numberVertices
+			} else if cc.AddVertex(entityID) {
+				numberVerticesAdded++
+			}
+		}
+
+		stats.NewVertices += numberVerticesAdded
+		log.Printf("Added %v declared vertices without edges\n", numberVerticesAdded)
 	}
 
-	log.Printf("Read %v rows from file %v\n", numRowsRead, filepath)
+	if stats.SkippedRows > 0 {
+		log.Printf("Skipped %v invalid rows\n", stats.SkippedRows)
+	}
+
+	if graph != nil {
+		log.Printf("Finding strongly connected components ...\n")
+		cc = *graph.StronglyConnectedComponents()
+	}
+
+	cc.vertexToDegree = degrees
 
-	return numRowsRead, &cc
+	return &stats, &cc
+}
+
+// verticesFromFile adds the entities listed in a file, one per row, to the connected components
+// so that entities without edges are assigned to their own connected component
+func verticesFromFile(filepath string, cc *ConnectedComponents, options ReadOptions, stats *RunStats) {
+
+	log.Printf("Reading vertices from file: %v\n", filepath)
+
+	// Open the file for reading and ensure it is closed
+	file, err := os.Open(filepath)
+	if err != nil {
+		log.Fatal("[!] Couldn't open vertices file ", err)
+	}
+	defer file.Close()
+
+	numberVerticesAdded := 0
+
+	// Parse the input file
+	parseCSVRows(file, 0,
+		func(lineNumber int, row []string) {
+			stats.RowsRead++
+
+			if reason := validateVertexRow(row); len(reason) > 0 {
+				options.ErrorHandler.Handle(lineNumber, row, reason)
+				stats.SkippedRows++
+				return
+			}
+
+			entityID := options.Normaliser.Normalise(row[0])
+			if len(entityID) == 0 {
+				options.ErrorHandler.Handle(lineNumber, row, "blank entity ID after normalisation")
+				stats.SkippedRows++
+				return
+			}
+
+			if cc.AddVertex(entityID) {
+				numberVerticesAdded++
+			}
+		},
+		func(lineNumber int, text string, reason string) {
+			stats.RowsRead++
+			options.ErrorHandler.Handle(lineNumber, []string{text}, reason)
+			stats.SkippedRows++
+		})
+
+	stats.NewVertices += numberVerticesAdded
+	log.Printf("Added %v vertices without edges from file %v\n", numberVerticesAdded, filepath)
+}
+
+// Parameters holds the running parameters of a connected component calculation
+type Parameters struct {
+	InputFilepath        string
+	OutputFilepath       string
+	OutputDelimiter      string
+	OutputFormat         string
+	MemberDelimiter      string
+	ParquetCompression   string
+	ParquetRowGroupSize  int
+	VertexFilepath       string
+	StatsFilepath        string
+	SQLiteFilepath       string
+	GraphOutputFilepath  string
+	GraphOutputFormat    string
+	CondensationFilepath string
+	SplitDirectory       string
+	SplitEdges           bool
+	SplitMinSize         int
+	SplitBucketSize      int
+	FragilityDirectory   string
+	KEdge                int
+	Communities          bool
+	CommunityMinSize     int
+	KCore                bool
+	SummaryFilepath      string
+	Metrics              bool
+	MetricsExactSize     int
+	Degree               bool
+	DegreeFilepath       string
+	OutputRawIDs         bool
+	SizeFilter           SizeFilter
+	TopN                 int
+	TopSampleSize        int
+	ReadOptions
+}
+
+// NewParameters sets up the parameters for a calculation with no optional behaviour enabled
+func NewParameters(inputFilepath string, outputFilepath string, outputDelimiter string) Parameters {
+	return Parameters{
+		InputFilepath:        inputFilepath,
+		OutputFilepath:       outputFilepath,
+		OutputDelimiter:      outputDelimiter,
+		OutputFormat:         "vertices",
+		MemberDelimiter:      "|",
+		ParquetCompression:   "snappy",
+		ParquetRowGroupSize:  1000000,
+		VertexFilepath:       "",
+		StatsFilepath:        "",
+		SQLiteFilepath:       "",
+		GraphOutputFilepath:  "",
+		GraphOutputFormat:    "",
+		CondensationFilepath: "",
+		SplitDirectory:       "",
+		SplitEdges:           false,
+		SplitMinSize:         2,
+		SplitBucketSize:      0,
+		FragilityDirectory:   "",
+		KEdge:                0,
+		Communities:          false,
+		CommunityMinSize:     100,
+		KCore:                false,
+		SummaryFilepath:      "",
+		Metrics:              false,
+		MetricsExactSize:     1000,
+		Degree:               false,
+		DegreeFilepath:       "",
+		OutputRawIDs:         false,
+		SizeFilter:           SizeFilter{},
+		TopN:                 0,
+		TopSampleSize:        5,
+		ReadOptions:          ReadOptions{BloomCapacity: 10000000, Workers: 1},
+	}
 }
 
 // resultsHeader builds the results file header
@@ -196,31 +626,48 @@ func sortedListVertices(vertexToComponent *map[string]int) *[]string {
 	return &keys
 }
 
-// writeConnectedComponentsToFile writes the vertex to connected component mapping to file
+// writeConnectedComponentsToFile writes the vertex to connected component mapping to file in an
+// output format, with one entry per raw entity ID if the raw IDs are output
 func writeVertexToConnectedComponentToFile(
 	vertexToComponent *map[string]int,
 	filepath string,
-	delimiter string) {
+	format string,
+	options WriterOptions) {
 
-	// Open the output CSV file for writing
+	// Open the output file for writing
 	outputFile, err := os.Create(filepath)
 	if err != nil {
 		log.Fatalf("[!] Unable to open output file %v for writing: %v\n", filepath, err)
 	}
 	defer outputFile.Close()
 
+	bufferedWriter := bufio.NewWriter(outputFile)
+	defer bufferedWriter.Flush()
+
 	// Write the header
-	fmt.Fprintln(outputFile, resultsHeader(delimiter))
+	writer := newVertexWriter(format, bufferedWriter, options)
+	writer.WriteHeader()
 
 	// Get a slice of sorted vertices
 	log.Printf("Sorting vertices ...\n")
 	sortedVertices := sortedListVertices(vertexToComponent)
 
-	// Write each vertex to its connected component
+	// Write each vertex to its connected component, if the component is within the size range
 	numberVerticesWritten := 0
 	for _, vertex := range *sortedVertices {
 
-		fmt.Fprintln(outputFile, buildResultsLine(vertex, (*vertexToComponent)[vertex], delimiter))
+		if options.SizeFilter.IsActive() &&
+			!options.SizeFilter.Includes(len((*options.ComponentToVertices)[(*vertexToComponent)[vertex]])) {
+			continue
+		}
+
+		if options.RawIDs != nil {
+			for _, rawID := range options.RawIDs.RawIDs(vertex) {
+				writer.WriteVertex(vertex, rawID, (*vertexToComponent)[vertex])
+			}
+		} else {
+			writer.WriteVertex(vertex, "", (*vertexToComponent)[vertex])
+		}
 
 		numberVerticesWritten++
 
@@ -228,6 +675,39 @@ func writeVertexToConnectedComponentToFile(
 			log.Printf("Number of vertices written to file: %v\n", numberVerticesWritten)
 		}
 	}
+
+	writer.Flush()
+}
+
+// writeResults writes the connected components within the size range to the output file in the
+// chosen output format, with any vertex columns after the other columns of the vertex formats
+func writeResults(cc *ConnectedComponents, params Parameters, vertexColumns []VertexColumn) {
+
+	filteredComponentToVertices, _, _ := filterComponents(&cc.connectedComponentToVertices, params.SizeFilter)
+
+	switch params.OutputFormat {
+	case "vertices", "jsonl", "json", "parquet":
+		options := WriterOptions{
+			Delimiter:           params.OutputDelimiter,
+			ComponentToVertices: &cc.connectedComponentToVertices,
+			ParquetCompression:  params.ParquetCompression,
+			ParquetRowGroupSize: params.ParquetRowGroupSize,
+			SizeFilter:          params.SizeFilter,
+			VertexColumns:       vertexColumns,
+		}
+		if params.OutputRawIDs {
+			options.RawIDs = params.Normaliser
+		}
+		writeVertexToConnectedComponentToFile(&cc.vertexToConnectedComponent, params.OutputFilepath,
+			params.OutputFormat, options)
+	case "members":
+		writeMembersToFile(filteredComponentToVertices, params.OutputFilepath, params.OutputDelimiter,
+			params.MemberDelimiter)
+	case "members-jsonl":
+		writeMembersToJSONLinesFile(filteredComponentToVertices, params.OutputFilepath)
+	default:
+		log.Fatalf("[!] Unknown output format: %v\n", params.OutputFormat)
+	}
 }
 
 // calculateConnectedComponents calculates the connected components from an edge list file
@@ -236,22 +716,248 @@ func calculateConnectedComponents(
 	outputFilepath string,
 	outputDelimiter string) {
 
+	calculateConnectedComponentsWithParameters(NewParameters(inputFilepath, outputFilepath, outputDelimiter))
+}
+
+// calculateConnectedComponentsWithParameters calculates the connected components using the full
+// set of running parameters
+func calculateConnectedComponentsWithParameters(params Parameters) {
+
 	// Display a summary of the running parameters
-	log.Printf("Parameter - Input file:            %v\n", inputFilepath)
-	log.Printf("Parameter - Output file:           %v\n", outputFilepath)
-	log.Printf("Parameter - Output file delimiter: %v\n", outputDelimiter)
+	log.Printf("Parameter - Input file:            %v\n", params.InputFilepath)
+	log.Printf("Parameter - Input format:          %v\n", inputFormat(params.InputFilepath, params.InputFormat))
+	log.Printf("Parameter - Directed:              %v\n", params.Directed)
+	log.Printf("Parameter - Workers:               %v\n", params.Workers)
+	if len(params.Query) > 0 {
+		log.Printf("Parameter - Input query:           %v\n", params.Query)
+	}
+	if len(params.WeightColumn) > 0 {
+		log.Printf("Parameter - Weight column:         %v\n", params.WeightColumn)
+	}
+	log.Printf("Parameter - Vertices file:         %v\n", params.VertexFilepath)
+	log.Printf("Parameter - Output file:           %v\n", params.OutputFilepath)
+	log.Printf("Parameter - Output file delimiter: %v\n", params.OutputDelimiter)
+	log.Printf("Parameter - Output format:         %v\n", params.OutputFormat)
+	log.Printf("Parameter - Output raw IDs:        %v\n", params.OutputRawIDs)
+	log.Printf("Parameter - Minimum size:          %v\n", params.SizeFilter.MinSize)
+	log.Printf("Parameter - Maximum size:          %v\n", params.SizeFilter.MaxSize)
+	if params.TopN > 0 {
+		log.Printf("Parameter - Top components:        %v\n", params.TopN)
+	}
+	log.Printf("Parameter - Count duplicates:      %v\n", params.CountDuplicates)
+	log.Printf("Parameter - Degree column:         %v\n", params.Degree)
+	log.Printf("Parameter - Degree distribution:   %v\n", params.DegreeFilepath)
+	log.Printf("Parameter - Collapse duplicates:   %v\n", params.CollapseDuplicates)
+	if len(params.DuplicateDetection) > 0 {
+		log.Printf("Parameter - Duplicate detection:   %v\n", params.DuplicateDetection)
+	}
+	if params.DuplicateDetection == "bloom" {
+		log.Printf("Parameter - Bloom filter capacity: %v\n", params.BloomCapacity)
+	}
+	log.Printf("Parameter - Deduplicated edges:    %v\n", params.DeduplicatedFilepath)
+	log.Printf("Parameter - Stats file:            %v\n", params.StatsFilepath)
+	log.Printf("Parameter - SQLite file:           %v\n", params.SQLiteFilepath)
+	log.Printf("Parameter - Graph output file:     %v\n", params.GraphOutputFilepath)
+	log.Printf("Parameter - Split directory:       %v\n", params.SplitDirectory)
+	log.Printf("Parameter - Fragility directory:   %v\n", params.FragilityDirectory)
+	if params.KEdge > 0 {
+		log.Printf("Parameter - Edge connectivity:     %v\n", params.KEdge)
+	}
+	if params.Communities {
+		log.Printf("Parameter - Community min size:    %v\n", params.CommunityMinSize)
+	}
+	log.Printf("Parameter - Core numbers:          %v\n", params.KCore)
+	log.Printf("Parameter - Summary file:          %v\n", params.SummaryFilepath)
+	if params.Metrics {
+		log.Printf("Parameter - Metrics exact size:    %v\n", params.MetricsExactSize)
+	}
+	if params.ErrorHandler != nil {
+		log.Printf("Parameter - On error:              %v\n", params.ErrorHandler.mode)
+		log.Printf("Parameter - Maximum errors:        %v\n", params.ErrorHandler.maxErrors)
+	}
+
+	// Preconditions
+	if params.Workers < 1 {
+		log.Fatal("[!] The number of workers parsing the input must be a positive integer")
+	}
+
+	switch inputFormat(params.InputFilepath, params.InputFormat) {
+	case "csv", "parquet", "arrow", "sqlite":
+	default:
+		if len(params.WeightColumn) > 0 {
+			log.Fatal("[!] A weight column can only be given for CSV, Parquet, Arrow or SQLite input")
+		}
+	}
+
+	if len(params.CondensationFilepath) > 0 && !params.Directed {
+		log.Fatal("[!] The condensation DAG can only be written for directed edges")
+	}
+
+	if len(params.FragilityDirectory) > 0 && params.Directed {
+		log.Fatal("[!] Articulation points, bridges and biconnected components can only be found for undirected edges")
+	}
+
+	if params.KEdge > 0 && params.Directed {
+		log.Fatal("[!] k-edge-connected subcomponents can only be found for undirected edges")
+	}
+
+	if params.Communities && params.Directed {
+		log.Fatal("[!] Communities can only be found for undirected edges")
+	}
+
+	if params.KCore && params.Directed {
+		log.Fatal("[!] Core numbers can only be found for undirected edges")
+	}
+
+	if params.Metrics && params.Directed {
+		log.Fatal("[!] Component metrics can only be found for undirected edges")
+	}
+
+	if params.Metrics && len(params.SummaryFilepath) == 0 {
+		log.Fatal("[!] Component metrics are written to the component summary, so a summary file must be given")
+	}
+
+	if params.KCore && len(params.SummaryFilepath) == 0 {
+		log.Fatal("[!] The maximum core of each component is written to the component summary, so a summary file must be given")
+	}
+
+	if (params.KEdge > 0 || params.Communities || params.KCore || params.Degree) &&
+		(params.OutputFormat == "members" || params.OutputFormat == "members-jsonl") {
+		log.Fatal("[!] The subcomponent, community, core number or degree of each vertex can't be written in the members output formats")
+	}
+
+	if len(params.OutputDelimiter) == 0 || len(params.MemberDelimiter) == 0 {
+		log.Fatal("[!] Cannot use a blank delimiter")
+	}
+
+	switch params.OutputFormat {
+	case "vertices":
+		if _, valid := delimiterRune(params.OutputDelimiter); !valid {
+			log.Fatalf("[!] The delimiter of the vertices output format must be a single character other than a quote or line break, found %q\n",
+				params.OutputDelimiter)
+		}
+	case "jsonl", "json", "members", "members-jsonl":
+	case "parquet":
+		parseParquetCompression(params.ParquetCompression)
+		if params.ParquetRowGroupSize < 1 {
+			log.Fatal("[!] The Parquet row group size must be a positive integer")
+		}
+	default:
+		log.Fatalf("[!] Unknown output format: %v\n", params.OutputFormat)
+	}
+
+	if len(params.GraphOutputFilepath) > 0 {
+		switch format := graphOutputFormat(params.GraphOutputFilepath, params.GraphOutputFormat); format {
+		case "graphml", "gexf", "dot":
+		default:
+			log.Fatalf("[!] Unknown graph output format: %v\n", format)
+		}
+	}
+
+	params.SizeFilter.validate()
+
+	if params.TopN < 0 || params.TopSampleSize < 0 {
+		log.Fatal("[!] The number of components and sample members in the top components report must not be negative")
+	}
+
+	if len(params.SplitDirectory) > 0 && (params.SplitMinSize < 1 || params.SplitBucketSize < 0) {
+		log.Fatal("[!] The minimum size of a split component must be a positive integer and the bucket size must not be negative")
+	}
+
+	if params.KEdge < 0 {
+		log.Fatal("[!] The edge connectivity of the subcomponents must not be negative")
+	}
+
+	if params.Communities && params.CommunityMinSize < 1 {
+		log.Fatal("[!] The minimum size of a component to find communities in must be a positive integer")
+	}
+
+	if params.Metrics && params.MetricsExactSize < 0 {
+		log.Fatal("[!] The largest size of a component with exact metrics must not be negative")
+	}
+
+	// The degrees are counted while reading the edges if they are output
+	params.TrackDegrees = params.Degree || len(params.DegreeFilepath) > 0
 
 	// Read the network and calculate the connected components
 	t0 := time.Now()
-	_, cc := connectedComponentsFromFile(inputFilepath)
+	stats, cc := connectedComponentsFromFile(params.InputFilepath, params.ReadOptions)
+	if len(params.VertexFilepath) > 0 {
+		verticesFromFile(params.VertexFilepath, cc, params.ReadOptions, stats)
+	}
+	params.ErrorHandler.Close()
 	log.Printf("Time taken to compute connected components: %v\n", time.Now().Sub(t0))
 	log.Printf("Found %v connected components\n", cc.numberConnectedComponents)
 
+	// Report the run statistics, including the components outside the size range
+	_, stats.FilteredComponents, stats.FilteredVertices = filterComponents(&cc.connectedComponentToVertices, params.SizeFilter)
+	stats.Log()
+	if len(params.StatsFilepath) > 0 {
+		writeStatsToFile(stats, params.StatsFilepath)
+	}
+
+	// Report the largest components instead of writing the results
+	if params.TopN > 0 {
+		log.Printf("Reporting the %v largest components ...\n", params.TopN)
+		writeTopReport(os.Stdout, topComponentsReport(cc, params), params.OutputDelimiter, params.MemberDelimiter)
+		log.Printf("Total time taken: %v\n", time.Now().Sub(t0))
+		return
+	}
+
+	// The structural analyses share a graph of the edges held in memory, read from the input once
+	// more. The line or row number of each edge is kept for the bridges.
+	var graph *UndirectedGraph
+	if params.KEdge > 0 || params.Communities || params.KCore || params.Metrics || len(params.FragilityDirectory) > 0 {
+		graph = undirectedGraphFromInput(params)
+	}
+
+	// Find the optional vertex and component summary columns
+	vertexColumns := []VertexColumn{}
+	summaryColumns := []SummaryColumn{}
+	if params.Degree {
+		vertexColumns = append(vertexColumns, degreeColumn(cc))
+	}
+	if params.KEdge > 0 {
+		vertexColumns = append(vertexColumns, kEdgeColumn(cc, graph, params))
+	}
+	if params.Communities {
+		vertexColumns = append(vertexColumns, communityColumn(cc, graph, params))
+	}
+	if params.KCore {
+		coreColumn, coreSummaryColumns := kCoreColumns(cc, graph)
+		vertexColumns = append(vertexColumns, coreColumn)
+		summaryColumns = append(summaryColumns, coreSummaryColumns...)
+	}
+	if params.Metrics {
+		summaryColumns = append(summaryColumns, metricsColumns(cc, graph, params)...)
+	}
+
 	// Write the connected components to a file
 	t1 := time.Now()
-	log.Printf("Writing results to file %v ...\n", outputFilepath)
-	writeVertexToConnectedComponentToFile(&cc.vertexToConnectedComponent, outputFilepath, outputDelimiter)
-	log.Printf("Time taken to write vertex to connected component mapping: %v\n", time.Now().Sub(t1))
+	log.Printf("Writing results to file %v ...\n", params.OutputFilepath)
+	writeResults(cc, params, vertexColumns)
+	if len(params.SummaryFilepath) > 0 {
+		writeComponentSummary(cc, params, summaryColumns)
+	}
+	if len(params.DegreeFilepath) > 0 {
+		writeDegreeDistributionToFile(cc, params.DegreeFilepath, params.OutputDelimiter)
+	}
+	if len(params.SQLiteFilepath) > 0 {
+		writeComponentsToSQLite(cc, params.SQLiteFilepath)
+	}
+	if len(params.GraphOutputFilepath) > 0 {
+		writeAnnotatedGraph(cc, params)
+	}
+	if len(params.CondensationFilepath) > 0 {
+		writeCondensationToFile(cc, params)
+	}
+	if len(params.SplitDirectory) > 0 {
+		writeComponentFiles(cc, params)
+	}
+	if len(params.FragilityDirectory) > 0 {
+		writeFragilityFiles(cc, graph, params)
+	}
+	log.Printf("Time taken to write results: %v\n", time.Now().Sub(t1))
 
 	// Show the total execution time
 	log.Printf("Total time taken: %v\n", time.Now().Sub(t0))
@@ -260,12 +966,119 @@ func calculateConnectedComponents(
 func main() {
 
 	// Command line arguments
-	inputFilepath := flag.String("input", "unipartite.csv", "Location of the input CSV file of edges")
+	inputFilepath := flag.String("input", "unipartite.csv", "Location of the input edge list or graph file")
+	inputFormatName := flag.String("input-format", "", "Format of the input file: csv, parquet, arrow, sqlite, graphml, gml, dot, pajek, matrixmarket, adjacency or metis (default from the file extension)")
+	sourceColumn := flag.String("source-column", "", "Name of the source column of a Parquet, Arrow or SQLite input (default the first column)")
+	targetColumn := flag.String("target-column", "", "Name of the target column of a Parquet, Arrow or SQLite input (default the second column)")
+	weightColumn := flag.String("weight-column", "", "Name of the edge weight column of a Parquet, Arrow or SQLite input, or number of the weight field of a CSV input counting from 1 (default no weights)")
+	query := flag.String("query", "", "SQL query returning the edges of a SQLite input")
+	neighbourSeparator := flag.String("neighbour-separator", " ", "Separator between the neighbours of an adjacency list input (space for any whitespace)")
+	directed := flag.Bool("directed", false, "Treat the edges as directed and find strongly connected components")
+	condensationFilepath := flag.String("condensation", "", "Location of an optional CSV file of the condensation DAG between strongly connected components")
+	vertexFilepath := flag.String("vertices", "", "Location of an optional CSV file of all entity IDs, one per row")
 	outputFilepath := flag.String("output", "results.csv", "Location of the output CSV file of entity ID to connected component ID")
 	delimiter := flag.String("delimiter", ",", "Delimiter for the CSV file of entity ID to connected component ID")
-	flag.Parse()
+	outputFormat := flag.String("output-format", "vertices", "Format of the output file: vertices, jsonl, json, parquet, members or members-jsonl")
+	memberDelimiter := flag.String("member-delimiter", "|", "Delimiter between the members of a component in the members output format")
+	parquetCompression := flag.String("parquet-compression", "snappy", "Compression of the Parquet output: none, snappy, gzip or zstd")
+	parquetRowGroupSize := flag.Int("parquet-row-group-size", 1000000, "Number of rows per row group of the Parquet output")
+	normaliseRules := flag.String("normalise", "", "Comma-separated entity ID normalisation rules: trim, casefold, nfc, nfkc, zeros")
+	rewritePattern := flag.String("rewrite-pattern", "", "Regular expression applied to entity IDs after the normalisation rules")
+	rewriteReplacement := flag.String("rewrite-replacement", "", "Replacement for matches of the rewrite pattern (may use $1 etc.)")
+	outputRawIDs := flag.Bool("output-raw-ids", false, "Output the raw entity IDs alongside the normalised entity ID")
+	onError := flag.String("on-error", "fail", "Action to take on an invalid input row: fail, skip or quarantine")
+	maxErrors := flag.Int("max-errors", 0, "Maximum number of invalid rows to skip before failing (0 for no limit)")
+	rejectsFilepath := flag.String("rejects", "rejects.csv", "Location of the CSV file of quarantined rows")
+	duplicateDetection := flag.String("dedup", "", "Detect duplicate and reversed edges: exact (holds every distinct edge in memory) or bloom (a fixed-size Bloom filter that may overcount)")
+	bloomCapacity := flag.Int("dedup-capacity", 10000000, "Number of distinct edges the Bloom filter of -dedup bloom is sized for, at 10 bits per edge")
+	deduplicatedFilepath := flag.String("dedup-output", "", "Location of an optional CSV file of the first occurrence of each edge")
+	workers := flag.Int("workers", 1, "Number of goroutines parsing a CSV edge list, with the edges still added in order")
+	countDuplicates := flag.Bool("count-duplicates", false, "Count duplicate edges, the same as -dedup exact unless -dedup bloom is given (holds every distinct edge in memory)")
+	statsFilepath := flag.String("stats", "", "Location of an optional JSON file of the run statistics")
+	graphOutputFilepath := flag.String("graph-output", "", "Location of an optional GraphML, GEXF or DOT file of the graph annotated with its components")
+	graphOutputFormat := flag.String("graph-output-format", "", "Format of the annotated graph file: graphml, gexf or dot (default from the file extension)")
+	splitDirectory := flag.String("split-dir", "", "Location of an optional directory to write one CSV file per component to")
+	splitEdges := flag.Bool("split-edges", false, "Write the edges of each component to its file as well as its members")
+	splitMinSize := flag.Int("split-min-size", 2, "Minimum number of members of a component written to its own file")
+	splitBucketSize := flag.Int("split-bucket-size", 0, "Maximum number of members per file, with larger components split into parts (0 for no limit)")
+	kEdge := flag.Int("kedge", 0, "Refine the components into k-edge-connected subcomponents for this k, written as an extra output column (0 to disable)")
+	communities := flag.Bool("communities", false, "Find communities within large components with the Louvain method, written as an extra output column")
+	communityMinSize := flag.Int("community-min-size", 100, "Minimum number of members of a component to find communities in")
+	kCore := flag.Bool("kcore", false, "Write the core number of each vertex as an extra output column, and the maximum core of each component to the summary file, which must be given")
+	summaryFilepath := flag.String("summary", "", "Location of an optional CSV file summarising each component")
+	metrics := flag.Bool("metrics", false, "Write the diameter, radius, centre and most central members of each component to the summary file")
+	metricsExactSize := flag.Int("metrics-exact-size", 1000, "Largest number of members of a component whose metrics are found exactly rather than approximated")
+	degree := flag.Bool("degree", false, "Write the degree of each vertex as an extra output column")
+	degreeFilepath := flag.String("degree-distribution", "", "Location of an optional CSV file of the number of vertices with each degree")
+	collapseDuplicates := flag.Bool("collapse-duplicates", false, "Count each distinct edge once in the degrees (holds every distinct edge in memory)")
+	fragilityDirectory := flag.String("fragility-dir", "", "Location of an optional directory to write the articulation points, bridges and biconnected components to")
+	minSize := flag.Int("min-size", 0, "Minimum number of members of a component written to the output file")
+	maxSize := flag.Int("max-size", 0, "Maximum number of members of a component written to the output file (0 for no limit)")
+	topN := flag.Int("top", 0, "Report the N largest components instead of writing the output file (the top subcommand defaults to 10)")
+	topSampleSize := flag.Int("top-sample", 5, "Number of sample and highest-degree members in the top components report")
+	sqliteFilepath := flag.String("sqlite", "", "Location of an optional SQLite database to write the vertices and components tables to")
+
+	// The top subcommand reports the largest components
+	isTopCommand := len(os.Args) > 1 && os.Args[1] == "top"
+	if isTopCommand {
+		flag.CommandLine.Parse(os.Args[2:])
+	} else {
+		flag.Parse()
+	}
+
+	if isTopCommand && *topN == 0 {
+		*topN = 10
+	}
+
+	// Build the running parameters from the command line arguments
+	params := NewParameters(*inputFilepath, *outputFilepath, *delimiter)
+	params.OutputFormat = *outputFormat
+	params.MemberDelimiter = *memberDelimiter
+	params.ParquetCompression = *parquetCompression
+	params.ParquetRowGroupSize = *parquetRowGroupSize
+	params.VertexFilepath = *vertexFilepath
+	params.InputFormat = *inputFormatName
+	params.SourceColumn = *sourceColumn
+	params.TargetColumn = *targetColumn
+	params.WeightColumn = *weightColumn
+	params.Query = *query
+	params.Directed = *directed
+	params.CondensationFilepath = *condensationFilepath
+	params.NeighbourSeparator = *neighbourSeparator
+	params.OutputRawIDs = *outputRawIDs
+	params.StatsFilepath = *statsFilepath
+	params.SQLiteFilepath = *sqliteFilepath
+	params.GraphOutputFilepath = *graphOutputFilepath
+	params.GraphOutputFormat = *graphOutputFormat
+	params.SizeFilter = SizeFilter{MinSize: *minSize, MaxSize: *maxSize}
+	params.TopN = *topN
+	params.TopSampleSize = *topSampleSize
+	params.SplitDirectory = *splitDirectory
+	params.SplitEdges = *splitEdges
+	params.SplitMinSize = *splitMinSize
+	params.SplitBucketSize = *splitBucketSize
+	params.FragilityDirectory = *fragilityDirectory
+	params.KEdge = *kEdge
+	params.Communities = *communities
+	params.CommunityMinSize = *communityMinSize
+	params.KCore = *kCore
+	params.SummaryFilepath = *summaryFilepath
+	params.Metrics = *metrics
+	params.MetricsExactSize = *metricsExactSize
+	params.Degree = *degree
+	params.DegreeFilepath = *degreeFilepath
+	params.CollapseDuplicates = *collapseDuplicates
+	params.CountDuplicates = *countDuplicates
+	params.DuplicateDetection = *duplicateDetection
+	params.BloomCapacity = *bloomCapacity
+	params.DeduplicatedFilepath = *deduplicatedFilepath
+	params.Workers = *workers
+	if len(*normaliseRules) > 0 || len(*rewritePattern) > 0 || *outputRawIDs {
+		params.Normaliser = NewNormaliser(*normaliseRules, *rewritePattern, *rewriteReplacement, *outputRawIDs)
+	}
+	params.ErrorHandler = NewRowErrorHandler(parseErrorMode(*onError), *maxErrors, *rejectsFilepath)
 
 	// Calculate the connected components given the command line arguments
 	log.Println("Connected component calculator")
-	calculateConnectedComponents(*inputFilepath, *outputFilepath, *delimiter)
+	calculateConnectedComponentsWithParameters(params)
 }
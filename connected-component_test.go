@@ -385,13 +385,75 @@ func TestAddEdgeFiveEdgesThreeComps(t *testing.T) {
 	}
 }
 
+func TestAddEdgeSelfLoop(t *testing.T) {
+	// A self-loop on a new vertex creates a single-vertex connected component
+	cc := NewConnectedComponents()
+	cc.AddEdge(EntityPair{EntityID1: "e-7", EntityID2: "e-7"})
+
+	if cc.numberConnectedComponents != 1 {
+		t.Fatalf("Expected 1 connected component, got %v\n", cc.numberConnectedComponents)
+	}
+
+	expectedComponentToVertices := map[int][]string{
+		0: []string{"e-7"},
+	}
+
+	if !reflect.DeepEqual(expectedComponentToVertices, cc.connectedComponentToVertices) {
+		t.Fatalf("Expected %v, got %v\n", expectedComponentToVertices, cc.connectedComponentToVertices)
+	}
+}
+
+func TestAddEdgeSelfLoopExistingVertex(t *testing.T) {
+	// A self-loop on a previously seen vertex doesn't change the connected components
+	cc := NewConnectedComponents()
+	cc.AddEdge(EntityPair{EntityID1: "e-1", EntityID2: "e-2"})
+	cc.AddEdge(EntityPair{EntityID1: "e-2", EntityID2: "e-2"})
+
+	if cc.numberConnectedComponents != 1 {
+		t.Fatalf("Expected 1 connected component, got %v\n", cc.numberConnectedComponents)
+	}
+
+	expectedComponentToVertices := map[int][]string{
+		0: []string{"e-1", "e-2"},
+	}
+
+	if !reflect.DeepEqual(expectedComponentToVertices, cc.connectedComponentToVertices) {
+		t.Fatalf("Expected %v, got %v\n", expectedComponentToVertices, cc.connectedComponentToVertices)
+	}
+}
+
+func TestAddVertex(t *testing.T) {
+	// A new vertex is a single-vertex connected component and a seen vertex is unchanged
+	cc := NewConnectedComponents()
+	cc.AddEdge(EntityPair{EntityID1: "e-1", EntityID2: "e-2"})
+	cc.AddVertex("e-3")
+	cc.AddVertex("e-1")
+
+	if cc.numberConnectedComponents != 2 {
+		t.Fatalf("Expected 2 connected components, got %v\n", cc.numberConnectedComponents)
+	}
+
+	if cc.nextConnectedComponentID != 2 {
+		t.Fatalf("Expected next connected component ID to be 2, got %v\n", cc.nextConnectedComponentID)
+	}
+
+	expectedComponentToVertices := map[int][]string{
+		0: []string{"e-1", "e-2"},
+		1: []string{"e-3"},
+	}
+
+	if !reflect.DeepEqual(expectedComponentToVertices, cc.connectedComponentToVertices) {
+		t.Fatalf("Expected %v, got %v\n", expectedComponentToVertices, cc.connectedComponentToVertices)
+	}
+}
+
 func TestConnectedComponentsFromFile1(t *testing.T) {
 
 	// Calculate connected components in file
-	numRowsRead, cc := connectedComponentsFromFile("./test/unipartite_1.csv")
+	stats, cc := connectedComponentsFromFile("./test/unipartite_1.csv", ReadOptions{})
 
-	if numRowsRead != 2 {
-		t.Fatalf("Expected to read 2 rows, read %v rows", numRowsRead)
+	if stats.RowsRead != 1 {
+		t.Fatalf("Expected to read 1 row, read %v rows", stats.RowsRead)
 	}
 
 	// Check the vertex to connected component assignment
@@ -408,10 +470,10 @@ func TestConnectedComponentsFromFile1(t *testing.T) {
 func TestConnectedComponentsFromFile2(t *testing.T) {
 
 	// Calculate connected components in file
-	numRowsRead, cc := connectedComponentsFromFile("./test/unipartite_2.csv")
+	stats, cc := connectedComponentsFromFile("./test/unipartite_2.csv", ReadOptions{})
 
-	if numRowsRead != 3 {
-		t.Fatalf("Expected to read 3 rows, read %v rows", numRowsRead)
+	if stats.RowsRead != 2 {
+		t.Fatalf("Expected to read 2 rows, read %v rows", stats.RowsRead)
 	}
 
 	// Check the vertex to connected component assignment
@@ -481,3 +543,39 @@ func TestCalculateConnectedComponents2(t *testing.T) {
 		t.Fatal("Actual results differ from expected results")
 	}
 }
+
+func TestCalculateConnectedComponentsWithVertices(t *testing.T) {
+
+	// Calculate the connected components of a graph with a self-loop and vertices without edges
+	params := NewParameters("./test/test-4/edge_list.csv", "./test/test-4/actual.csv", ",")
+	params.VertexFilepath = "./test/test-4/vertices.csv"
+	calculateConnectedComponentsWithParameters(params)
+
+	// Read the actual and expected results
+	if !FilesHaveSameContent("./test/test-4/actual.csv", "./test/test-4/expected.csv") {
+		t.Fatal("Actual results differ from expected results")
+	}
+}
+
+func TestAddEdgeCountsEdges(t *testing.T) {
+	// Edges are counted in their connected component, including self-loops and merging edges
+	cc := NewConnectedComponents()
+	cc.AddEdge(EntityPair{EntityID1: "e-1", EntityID2: "e-2"})
+	cc.AddEdge(EntityPair{EntityID1: "e-2", EntityID2: "e-3"})
+	cc.AddEdge(EntityPair{EntityID1: "e-4", EntityID2: "e-5"})
+	cc.AddEdge(EntityPair{EntityID1: "e-5", EntityID2: "e-5"})
+	cc.AddEdge(EntityPair{EntityID1: "e-6", EntityID2: "e-6"})
+	cc.AddEdge(EntityPair{EntityID1: "e-1", EntityID2: "e-3"})
+	cc.AddEdge(EntityPair{EntityID1: "e-3", EntityID2: "e-7"})
+	cc.AddEdge(EntityPair{EntityID1: "e-5", EntityID2: "e-3"})
+	cc.AddVertex("e-8")
+
+	expectedComponentToEdges := map[int]int{
+		0: 7,
+		2: 1,
+	}
+
+	if !reflect.DeepEqual(expectedComponentToEdges, cc.connectedComponentToEdges) {
+		t.Fatalf("Expected %v, got %v\n", expectedComponentToEdges, cc.connectedComponentToEdges)
+	}
+}
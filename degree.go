@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// degreeColumn returns the output column giving the degree of each vertex counted while reading
+// the edges
+func degreeColumn(cc *ConnectedComponents) VertexColumn {
+	return VertexColumn{Header: "Degree", Name: "degree", Integers: cc.vertexToDegree}
+}
+
+// degreeDistribution returns the number of vertices with each degree, including the vertices
+// without edges, with the degrees in order
+func degreeDistribution(cc *ConnectedComponents) ([]int, map[int]int) {
+
+	counts := map[int]int{}
+	for vertex := range cc.vertexToConnectedComponent {
+		counts[cc.vertexToDegree[vertex]]++
+	}
+
+	degrees := make([]int, 0, len(counts))
+	for degree := range counts {
+		degrees = append(degrees, degree)
+	}
+	sort.Ints(degrees)
+
+	return degrees, counts
+}
+
+// degreeDistributionHeader builds the header of the degree distribution file
+func degreeDistributionHeader(delimiter string) string {
+
+	// Precondition
+	if len(delimiter) == 0 {
+		log.Fatal("Cannot use a blank delimiter")
+	}
+
+	return "Degree" + delimiter + "Count"
+}
+
+// writeDegreeDistributionToFile writes the number of vertices with each degree to a file
+func writeDegreeDistributionToFile(cc *ConnectedComponents, filepath string, delimiter string) {
+
+	log.Printf("Writing degree distribution to file %v ...\n", filepath)
+
+	outputFile, err := os.Create(filepath)
+	if err != nil {
+		log.Fatalf("[!] Unable to open degree distribution file %v for writing: %v\n", filepath, err)
+	}
+	defer outputFile.Close()
+
+	bufferedWriter := bufio.NewWriter(outputFile)
+	defer bufferedWriter.Flush()
+
+	fmt.Fprintln(bufferedWriter, degreeDistributionHeader(delimiter))
+
+	degrees, counts := degreeDistribution(cc)
+	for _, degree := range degrees {
+		fmt.Fprintln(bufferedWriter, strconv.Itoa(degree)+delimiter+strconv.Itoa(counts[degree]))
+	}
+
+	if len(degrees) > 0 {
+		log.Printf("Found a maximum degree of %v\n", degrees[len(degrees)-1])
+	}
+}
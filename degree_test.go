@@ -0,0 +1,56 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDegreeDistribution(t *testing.T) {
+
+	cc := NewConnectedComponents()
+	cc.vertexToDegree = map[string]int{}
+	for _, pair := range []EntityPair{{"a", "b"}, {"b", "c"}, {"c", "a"}, {"c", "d"}} {
+		cc.AddEdge(pair)
+		cc.vertexToDegree[pair.EntityID1]++
+		cc.vertexToDegree[pair.EntityID2]++
+	}
+	cc.AddVertex("e")
+
+	degrees, counts := degreeDistribution(&cc)
+
+	expectedDegrees := []int{0, 1, 2, 3}
+	expectedCounts := map[int]int{0: 1, 1: 1, 2: 2, 3: 1}
+
+	if !reflect.DeepEqual(expectedDegrees, degrees) {
+		t.Fatalf("Expected %v, got %v\n", expectedDegrees, degrees)
+	}
+
+	if !reflect.DeepEqual(expectedCounts, counts) {
+		t.Fatalf("Expected %v, got %v\n", expectedCounts, counts)
+	}
+}
+
+func TestCalculateDegrees(t *testing.T) {
+
+	// The degree distribution counts every edge, including the repeated and reversed edges, and
+	// both ends of the self-loop
+	params := NewParameters("./test/test-10/edge_list.csv", "./test/test-10/actual.csv", ",")
+	params.VertexFilepath = "./test/test-10/vertices.csv"
+	params.DegreeFilepath = "./test/test-10/actual_degree_distribution.csv"
+	calculateConnectedComponentsWithParameters(params)
+
+	if !FilesHaveSameContent("./test/test-10/actual_degree_distribution.csv", "./test/test-10/expected_degree_distribution.csv") {
+		t.Fatal("Actual degree distribution differs from expected degree distribution")
+	}
+
+	// With duplicates collapsed the repeated and reversed edges count once
+	params = NewParameters("./test/test-10/edge_list.csv", "./test/test-10/actual_degree_collapsed.csv", ",")
+	params.VertexFilepath = "./test/test-10/vertices.csv"
+	params.Degree = true
+	params.CollapseDuplicates = true
+	calculateConnectedComponentsWithParameters(params)
+
+	if !FilesHaveSameContent("./test/test-10/actual_degree_collapsed.csv", "./test/test-10/expected_degree_collapsed.csv") {
+		t.Fatal("Actual results differ from expected results")
+	}
+}
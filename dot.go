@@ -0,0 +1,455 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// DOTEdgeSource reads the nodes and edges of a Graphviz DOT file
+type DOTEdgeSource struct {
+	filepath string
+}
+
+// dotToken is an ID, edge operator or punctuation character of a DOT file with its line number.
+// Quoted and HTML strings are IDs that are never keywords.
+type dotToken struct {
+	text       string
+	lineNumber int
+	isID       bool
+	isQuoted   bool
+}
+
+// dotLexer splits a DOT file into tokens, skipping comments and preprocessor lines
+type dotLexer struct {
+	r           *bufio.Reader
+	lineNumber  int
+	startOfLine bool
+}
+
+// readRune reads the next character, counting lines
+func (l *dotLexer) readRune() (rune, bool) {
+
+	c, _, err := l.r.ReadRune()
+	if err == io.EOF {
+		return 0, false
+	}
+	if err != nil {
+		log.Fatal("[!] Error reading DOT file: ", err)
+	}
+
+	if c == '\n' {
+		l.lineNumber++
+	}
+
+	return c, true
+}
+
+// peekRune returns the next character without reading it
+func (l *dotLexer) peekRune() rune {
+	c, _, err := l.r.ReadRune()
+	if err != nil {
+		return 0
+	}
+	l.r.UnreadRune()
+	return c
+}
+
+// fail stops with a parse error at the current line
+func (l *dotLexer) fail(message string) {
+	log.Fatalf("[!] Error parsing DOT file at line %v: %v\n", l.lineNumber, message)
+}
+
+// isDOTIDRune returns whether a character can be part of an unquoted alphanumeric ID
+func isDOTIDRune(c rune) bool {
+	return c == '_' || c >= 0x80 || unicode.IsLetter(c) || unicode.IsDigit(c)
+}
+
+// next returns the next token and false at the end of the file
+func (l *dotLexer) next() (dotToken, bool) {
+
+	for {
+		c, ok := l.readRune()
+		if !ok {
+			return dotToken{}, false
+		}
+
+		startOfLine := l.startOfLine
+		l.startOfLine = c == '\n' || (startOfLine && (c == ' ' || c == '\t' || c == '\r'))
+
+		switch {
+		case unicode.IsSpace(c):
+			continue
+
+		case c == '#' && startOfLine, c == '/' && l.peekRune() == '/':
+			// Preprocessor output and line comments run to the end of the line
+			for ok && c != '\n' {
+				c, ok = l.readRune()
+			}
+			l.startOfLine = true
+
+		case c == '/' && l.peekRune() == '*':
+			// Block comments run to the closing */
+			l.readRune()
+			previous := rune(0)
+			for {
+				if c, ok = l.readRune(); !ok {
+					l.fail("unterminated comment")
+				}
+				if previous == '*' && c == '/' {
+					break
+				}
+				previous = c
+			}
+
+		case c == '-' && (l.peekRune() == '-' || l.peekRune() == '>'):
+			next, _ := l.readRune()
+			return dotToken{text: string([]rune{c, next}), lineNumber: l.lineNumber}, true
+
+		case strings.ContainsRune("{}[];,=:", c):
+			return dotToken{text: string(c), lineNumber: l.lineNumber}, true
+
+		case c == '"':
+			return l.quoted(), true
+
+		case c == '<':
+			return l.html(), true
+
+		case isDOTIDRune(c) || c == '-' || c == '.':
+			token := dotToken{lineNumber: l.lineNumber, isID: true}
+			var text strings.Builder
+			text.WriteRune(c)
+			for isDOTIDRune(l.peekRune()) || l.peekRune() == '.' {
+				c, _ = l.readRune()
+				text.WriteRune(c)
+			}
+			token.text = text.String()
+			return token, true
+
+		default:
+			l.fail("unexpected character " + string(c))
+		}
+	}
+}
+
+// quoted reads the rest of a double-quoted string, and of any strings concatenated to it with +
+func (l *dotLexer) quoted() dotToken {
+
+	token := dotToken{lineNumber: l.lineNumber, isID: true, isQuoted: true}
+	var text strings.Builder
+
+	for {
+		c, ok := l.readRune()
+		if !ok {
+			l.fail("unterminated string")
+		}
+
+		if c == '"' {
+			// Look for a + concatenating another quoted string
+			for unicode.IsSpace(l.peekRune()) {
+				l.readRune()
+			}
+			if l.peekRune() != '+' {
+				break
+			}
+			l.readRune()
+			for unicode.IsSpace(l.peekRune()) {
+				l.readRune()
+			}
+			if c, _ = l.readRune(); c != '"' {
+				l.fail("expected a quoted string after +")
+			}
+			continue
+		}
+
+		if c == '\\' {
+			// Escaped quotes and backslashes are unescaped and escaped newlines continue the line
+			next, _ := l.readRune()
+			switch next {
+			case '"', '\\':
+				text.WriteRune(next)
+			case '\n':
+			case '\r':
+				if l.peekRune() == '\n' {
+					l.readRune()
+				}
+			default:
+				text.WriteRune(c)
+				text.WriteRune(next)
+			}
+			continue
+		}
+
+		text.WriteRune(c)
+	}
+
+	token.text = text.String()
+	return token
+}
+
+// html reads the rest of an HTML string delimited by balanced angle brackets
+func (l *dotLexer) html() dotToken {
+
+	token := dotToken{lineNumber: l.lineNumber, isID: true, isQuoted: true}
+	var text strings.Builder
+	depth := 1
+
+	for {
+		c, ok := l.readRune()
+		if !ok {
+			l.fail("unterminated HTML string")
+		}
+
+		if c == '<' {
+			depth++
+		} else if c == '>' {
+			depth--
+			if depth == 0 {
+				break
+			}
+		}
+
+		text.WriteRune(c)
+	}
+
+	token.text = text.String()
+	return token
+}
+
+// dotParser parses the statements of a DOT file, passing the nodes and edges to the handlers
+type dotParser struct {
+	lexer    *dotLexer
+	peeked   []dotToken
+	handlers EdgeHandlers
+
+	// subgraphs holds the nodes of each open subgraph, so that edges to a subgraph connect all of them
+	subgraphs [][]string
+}
+
+// peek returns the token a number of positions ahead without reading it
+func (p *dotParser) peek(ahead int) dotToken {
+	for len(p.peeked) <= ahead {
+		token, ok := p.lexer.next()
+		if !ok {
+			token = dotToken{lineNumber: p.lexer.lineNumber}
+		}
+		p.peeked = append(p.peeked, token)
+	}
+	return p.peeked[ahead]
+}
+
+// read reads the next token
+func (p *dotParser) read() dotToken {
+	token := p.peek(0)
+	p.peeked = p.peeked[1:]
+	return token
+}
+
+// expect reads the next token, which must have the given text
+func (p *dotParser) expect(text string) {
+	if token := p.read(); token.text != text || token.isQuoted {
+		p.lexer.lineNumber = token.lineNumber
+		p.lexer.fail("expected " + text + " but found " + token.text)
+	}
+}
+
+// isKeyword returns whether a token is a case-insensitive DOT keyword
+func (t dotToken) isKeyword(keyword string) bool {
+	return t.isID && !t.isQuoted && strings.EqualFold(t.text, keyword)
+}
+
+// mention adds a node to the open subgraphs
+func (p *dotParser) mention(entityID string) {
+	if len(p.subgraphs) > 0 {
+		p.subgraphs[len(p.subgraphs)-1] = append(p.subgraphs[len(p.subgraphs)-1], entityID)
+	}
+}
+
+// parseFile parses each graph in the file
+func (p *dotParser) parseFile() {
+
+	for len(p.peek(0).text) > 0 || p.peek(0).isQuoted {
+
+		if p.peek(0).isKeyword("strict") {
+			p.read()
+		}
+
+		if token := p.read(); !token.isKeyword("graph") && !token.isKeyword("digraph") {
+			p.lexer.lineNumber = token.lineNumber
+			p.lexer.fail("expected graph or digraph but found " + token.text)
+		}
+
+		if p.peek(0).isID {
+			p.read()
+		}
+
+		p.expect("{")
+		p.parseStatements()
+		p.expect("}")
+	}
+}
+
+// parseStatements parses a statement list up to a closing brace
+func (p *dotParser) parseStatements() {
+
+	for {
+		token := p.peek(0)
+
+		if (token.text == "}" && !token.isQuoted) || (len(token.text) == 0 && !token.isQuoted) {
+			return
+		}
+
+		p.parseStatement()
+
+		if p.peek(0).text == ";" && !p.peek(0).isQuoted {
+			p.read()
+		}
+	}
+}
+
+// parseAttributeLists skips any attribute lists following a statement
+func (p *dotParser) parseAttributeLists() {
+
+	for p.peek(0).text == "[" && !p.peek(0).isQuoted {
+		p.read()
+		for {
+			token := p.read()
+			if token.text == "]" && !token.isQuoted {
+				break
+			}
+			if len(token.text) == 0 && !token.isQuoted {
+				p.lexer.fail("unterminated attribute list")
+			}
+		}
+	}
+}
+
+// parseStatement parses a node, edge, attribute or subgraph statement
+func (p *dotParser) parseStatement() {
+
+	token := p.peek(0)
+	next := p.peek(1)
+
+	// Attribute statements and graph attributes
+	if (token.isKeyword("graph") || token.isKeyword("node") || token.isKeyword("edge")) && next.text == "[" && !next.isQuoted {
+		p.read()
+		p.parseAttributeLists()
+		return
+	}
+
+	if token.isID && next.text == "=" && !next.isQuoted {
+		p.read()
+		p.read()
+		p.read()
+		return
+	}
+
+	// Node and edge statements, whose operands may be subgraphs
+	lineNumber := token.lineNumber
+	left, isNode := p.parseOperand()
+
+	if !(p.peek(0).text == "--" || p.peek(0).text == "->") || p.peek(0).isQuoted {
+		if isNode {
+			p.handlers.Vertex(lineNumber, left[0])
+		}
+		p.parseAttributeLists()
+		return
+	}
+
+	for (p.peek(0).text == "--" || p.peek(0).text == "->") && !p.peek(0).isQuoted {
+		p.read()
+		right, _ := p.parseOperand()
+
+		for _, source := range left {
+			for _, target := range right {
+				p.handlers.Edge(lineNumber, []string{source, target}, "")
+			}
+		}
+
+		left = right
+	}
+
+	p.parseAttributeLists()
+}
+
+// parseOperand parses a node ID, ignoring any port, or a subgraph, and returns the nodes and whether
+// it is a single node ID
+func (p *dotParser) parseOperand() ([]string, bool) {
+
+	token := p.peek(0)
+
+	if token.isKeyword("subgraph") || (token.text == "{" && !token.isQuoted) {
+		return p.parseSubgraph(), false
+	}
+
+	token = p.read()
+	if !token.isID {
+		p.lexer.lineNumber = token.lineNumber
+		p.lexer.fail("expected a node ID but found " + token.text)
+	}
+
+	// Ports and compass points don't change the node
+	for p.peek(0).text == ":" && !p.peek(0).isQuoted {
+		p.read()
+		p.read()
+	}
+
+	p.mention(token.text)
+
+	return []string{token.text}, true
+}
+
+// parseSubgraph parses a subgraph and returns its distinct nodes
+func (p *dotParser) parseSubgraph() []string {
+
+	if p.peek(0).isKeyword("subgraph") {
+		p.read()
+		if p.peek(0).isID {
+			p.read()
+		}
+	}
+
+	p.subgraphs = append(p.subgraphs, []string{})
+
+	p.expect("{")
+	p.parseStatements()
+	p.expect("}")
+
+	mentioned := p.subgraphs[len(p.subgraphs)-1]
+	p.subgraphs = p.subgraphs[:len(p.subgraphs)-1]
+
+	// Remove repeated nodes, keeping the order in which they are first mentioned
+	seen := map[string]bool{}
+	nodes := []string{}
+	for _, node := range mentioned {
+		if !seen[node] {
+			seen[node] = true
+			nodes = append(nodes, node)
+			p.mention(node)
+		}
+	}
+
+	return nodes
+}
+
+// ReadEdges parses the DOT file, passing each node statement as a vertex and each edge of an edge
+// statement as an edge. An edge to or from a subgraph connects every node of the subgraph, and
+// attributes, ports and the direction of edges are ignored.
+func (s DOTEdgeSource) ReadEdges(handlers EdgeHandlers) {
+
+	// Open the file for reading and ensure it is closed
+	file, err := os.Open(s.filepath)
+	if err != nil {
+		log.Fatal("[!] Couldn't open DOT file ", err)
+	}
+	defer file.Close()
+
+	parser := dotParser{
+		lexer:    &dotLexer{r: bufio.NewReader(file), lineNumber: 1, startOfLine: true},
+		handlers: handlers,
+	}
+	parser.parseFile()
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"hash/fnv"
+	"log"
+)
+
+// EdgeDeduplicator records the edges seen to find duplicate edges
+type EdgeDeduplicator interface {
+	// AddWithDirection adds an edge and returns whether it had already been seen, and whether it
+	// had only been seen in the reverse direction
+	AddWithDirection(pair EntityPair) (bool, bool)
+}
+
+// newEdgeDeduplicator sets up the exact or Bloom filter set of edges seen, or nil if duplicates
+// aren't detected, where the Bloom filter is sized for a number of distinct edges
+func newEdgeDeduplicator(detection string, directed bool, capacity int) EdgeDeduplicator {
+
+	switch detection {
+	case "":
+		return nil
+	case "exact":
+		if directed {
+			return NewDirectedEdgeSet()
+		}
+		return NewEdgeSet()
+	case "bloom":
+		return NewBloomEdgeSet(capacity, directed)
+	}
+
+	log.Fatalf("[!] Unknown duplicate detection %v, expected exact or bloom\n", detection)
+	return nil
+}
+
+// The directions in which an edge has been seen, relative to its entity IDs in sorted order
+const (
+	edgeSeenForward  uint8 = 1
+	edgeSeenReversed uint8 = 2
+)
+
+// EdgeSet records the edges seen, ignoring the direction of each edge unless the edges are directed
+type EdgeSet struct {
+	pairs    map[EntityPair]uint8
+	directed bool
+}
+
+// NewEdgeSet sets up a new empty EdgeSet
+func NewEdgeSet() *EdgeSet {
+	return &EdgeSet{
+		pairs: map[EntityPair]uint8{},
+	}
+}
+
+// NewDirectedEdgeSet sets up a new empty EdgeSet in which an edge and its reverse are different
+func NewDirectedEdgeSet() *EdgeSet {
+	return &EdgeSet{
+		pairs:    map[EntityPair]uint8{},
+		directed: true,
+	}
+}
+
+// unorderedPair returns the pair with the entity IDs in sorted order
+func unorderedPair(pair EntityPair) EntityPair {
+	if pair.EntityID2 < pair.EntityID1 {
+		return EntityPair{EntityID1: pair.EntityID2, EntityID2: pair.EntityID1}
+	}
+	return pair
+}
+
+// AddWithDirection adds an edge to the set and returns whether it had already been seen, and
+// whether it had only been seen in the reverse direction, which is never the case for directed
+// edges or self-loops
+func (s *EdgeSet) AddWithDirection(pair EntityPair) (bool, bool) {
+
+	key := pair
+	direction := edgeSeenForward
+	if !s.directed {
+		key = unorderedPair(pair)
+		if key != pair {
+			direction = edgeSeenReversed
+		}
+	}
+
+	seen := s.pairs[key]
+	s.pairs[key] = seen | direction
+
+	return seen != 0, seen != 0 && seen&direction == 0
+}
+
+// bloomBitsPerEdge and bloomHashes give a Bloom filter a false positive rate of about 1% when it
+// holds as many edges as it was sized for
+const (
+	bloomBitsPerEdge = 10
+	bloomHashes      = 7
+)
+
+// BloomEdgeSet records the edges seen in a Bloom filter, which takes a fixed amount of memory
+// however many edges are added but may take a new edge for one already seen, more often once it
+// holds more edges than it was sized for. Each edge is held in the direction it was seen.
+type BloomEdgeSet struct {
+	bits       []uint64
+	numberBits uint64
+	directed   bool
+}
+
+// NewBloomEdgeSet sets up a new empty BloomEdgeSet sized for a number of distinct edges
+func NewBloomEdgeSet(capacity int, directed bool) *BloomEdgeSet {
+
+	// Precondition
+	if capacity < 1 {
+		log.Fatal("The number of edges of a Bloom filter must be a positive integer")
+	}
+
+	numberBits := uint64(capacity) * bloomBitsPerEdge
+	return &BloomEdgeSet{
+		bits:       make([]uint64, (numberBits+63)/64),
+		numberBits: numberBits,
+		directed:   directed,
+	}
+}
+
+// mixHash spreads the bits of a hash over the whole word, with the finaliser of SplitMix64, as
+// FNV hashes of similar entity IDs differ in few bits
+func mixHash(h uint64) uint64 {
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return h
+}
+
+// positions returns the bits of the filter set for an edge, found by double hashing
+func (b *BloomEdgeSet) positions(pair EntityPair) [bloomHashes]uint64 {
+
+	hash := fnv.New64a()
+	hash.Write([]byte(pair.EntityID1))
+	hash.Write([]byte{0})
+	hash.Write([]byte(pair.EntityID2))
+	h1 := mixHash(hash.Sum64())
+	h2 := mixHash(h1) | 1
+
+	positions := [bloomHashes]uint64{}
+	for i := range positions {
+		positions[i] = (h1 + uint64(i)*h2) % b.numberBits
+	}
+
+	return positions
+}
+
+// contains returns whether an edge may have been added in the direction given
+func (b *BloomEdgeSet) contains(pair EntityPair) bool {
+	for _, position := range b.positions(pair) {
+		if b.bits[position/64]&(1<<(position%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// add sets the bits of an edge in the direction given
+func (b *BloomEdgeSet) add(pair EntityPair) {
+	for _, position := range b.positions(pair) {
+		b.bits[position/64] |= 1 << (position % 64)
+	}
+}
+
+// AddWithDirection adds an edge to the filter and returns whether it has probably been seen
+// before, and whether it has probably only been seen in the reverse direction, which is never the
+// case for directed edges or self-loops
+func (b *BloomEdgeSet) AddWithDirection(pair EntityPair) (bool, bool) {
+
+	if b.contains(pair) {
+		return true, false
+	}
+
+	b.add(pair)
+
+	reverse := EntityPair{EntityID1: pair.EntityID2, EntityID2: pair.EntityID1}
+	if !b.directed && reverse != pair && b.contains(reverse) {
+		return true, true
+	}
+
+	return false, false
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"strconv"
+	"testing"
+)
+
+// addEdges adds edges to a deduplicator and returns the number of duplicate and reversed edges
+func addEdges(deduplicator EdgeDeduplicator, pairs []EntityPair) (int, int) {
+
+	numberDuplicates := 0
+	numberReversed := 0
+	for _, pair := range pairs {
+		duplicate, reversed := deduplicator.AddWithDirection(pair)
+		if duplicate {
+			numberDuplicates++
+		}
+		if reversed {
+			numberReversed++
+		}
+	}
+
+	return numberDuplicates, numberReversed
+}
+
+func TestEdgeDeduplicators(t *testing.T) {
+
+	// The second and third edges are duplicates of the first, with the second reversed, and the
+	// repeated self-loop is a duplicate but not reversed
+	pairs := []EntityPair{
+		{EntityID1: "e-1", EntityID2: "e-2"},
+		{EntityID1: "e-2", EntityID2: "e-1"},
+		{EntityID1: "e-1", EntityID2: "e-2"},
+		{EntityID1: "e-3", EntityID2: "e-3"},
+		{EntityID1: "e-3", EntityID2: "e-3"},
+		{EntityID1: "e-2", EntityID2: "e-3"},
+	}
+
+	for _, detection := range []string{"exact", "bloom"} {
+		if duplicates, reversed := addEdges(newEdgeDeduplicator(detection, false, 100), pairs); duplicates != 3 || reversed != 1 {
+			t.Fatalf("Expected 3 duplicates and 1 reversed edge with %v detection, got %v and %v\n", detection, duplicates, reversed)
+		}
+
+		// Directed edges are never reversed duplicates
+		if duplicates, reversed := addEdges(newEdgeDeduplicator(detection, true, 100), pairs); duplicates != 2 || reversed != 0 {
+			t.Fatalf("Expected 2 duplicates and no reversed edges with directed %v detection, got %v and %v\n", detection, duplicates, reversed)
+		}
+	}
+}
+
+func TestBloomEdgeSetFalsePositiveRate(t *testing.T) {
+
+	// A filter holding the number of edges it was sized for takes few new edges for seen ones, which
+	// are checked without adding them
+	numberEdges := 10000
+	bloomEdgeSet := NewBloomEdgeSet(numberEdges, false)
+	for i := 0; i < numberEdges; i++ {
+		bloomEdgeSet.AddWithDirection(EntityPair{EntityID1: "a-" + strconv.Itoa(i), EntityID2: "b"})
+	}
+
+	numberFalsePositives := 0
+	for i := 0; i < numberEdges; i++ {
+		if bloomEdgeSet.contains(EntityPair{EntityID1: "c", EntityID2: "d-" + strconv.Itoa(i)}) {
+			numberFalsePositives++
+		}
+	}
+
+	if numberFalsePositives > numberEdges*3/100 {
+		t.Fatalf("Expected a false positive rate below 3%%, got %v false positives in %v\n", numberFalsePositives, numberEdges)
+	}
+}
+
+func TestWriteDeduplicatedEdges(t *testing.T) {
+
+	// The first occurrence of each edge is written, with IDs holding the delimiter quoted
+	params := NewParameters("./test/test-11/edge_list.csv", "./test/test-11/actual.csv", ",")
+	params.DeduplicatedFilepath = "./test/test-11/actual_deduplicated.csv"
+	calculateConnectedComponentsWithParameters(params)
+
+	if !FilesHaveSameContent("./test/test-11/actual_deduplicated.csv", "./test/test-11/expected_deduplicated.csv") {
+		t.Fatal("Actual deduplicated edge list differs from expected deduplicated edge list")
+	}
+}
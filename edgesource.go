@@ -0,0 +1,221 @@
+package main
+
+import (
+	"log"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// EdgeHandlers receive what an edge source reads: each edge as a row of source and target entity
+// IDs with the text of its weight, blank if the input gives none, each vertex declared by the
+// input, and each part of the input that can't be parsed with its text, all with their line or
+// row number
+type EdgeHandlers struct {
+	Edge       func(lineNumber int, row []string, weight string)
+	Vertex     func(lineNumber int, entityID string)
+	ParseError func(lineNumber int, text string, reason string)
+}
+
+// EdgeSource reads the edges of a graph from an input file
+type EdgeSource interface {
+	ReadEdges(handlers EdgeHandlers)
+}
+
+// inputFormat returns the format of the edge list file, given explicitly or by its extension
+func inputFormat(filepath string, format string) string {
+
+	if len(format) > 0 {
+		return format
+	}
+
+	switch strings.ToLower(path.Ext(filepath)) {
+	case ".parquet", ".pq":
+		return "parquet"
+	case ".arrow", ".arrows", ".feather", ".ipc":
+		return "arrow"
+	case ".db", ".sqlite", ".sqlite3":
+		return "sqlite"
+	case ".graphml":
+		return "graphml"
+	case ".gml":
+		return "gml"
+	case ".dot", ".gv":
+		return "dot"
+	case ".net":
+		return "pajek"
+	case ".mtx":
+		return "matrixmarket"
+	case ".adj", ".adjlist":
+		return "adjacency"
+	case ".metis":
+		return "metis"
+	}
+
+	return "csv"
+}
+
+// newEdgeSource sets up the edge source for an input file in the given format
+func newEdgeSource(filepath string, format string, options ReadOptions) EdgeSource {
+
+	switch format {
+	case "csv":
+		if options.Workers > 1 {
+			return ParallelCSVEdgeSource{filepath: filepath, weightField: csvWeightField(options.WeightColumn),
+				workers: options.Workers, chunkSize: csvChunkSize}
+		}
+		return CSVEdgeSource{filepath: filepath, weightField: csvWeightField(options.WeightColumn)}
+	case "parquet":
+		return ParquetEdgeSource{filepath: filepath, sourceColumn: options.SourceColumn, targetColumn: options.TargetColumn,
+			weightColumn: options.WeightColumn}
+	case "arrow":
+		return ArrowEdgeSource{filepath: filepath, sourceColumn: options.SourceColumn, targetColumn: options.TargetColumn,
+			weightColumn: options.WeightColumn}
+	case "sqlite":
+		return SQLiteEdgeSource{filepath: filepath, query: options.Query, sourceColumn: options.SourceColumn,
+			targetColumn: options.TargetColumn, weightColumn: options.WeightColumn}
+	case "graphml":
+		return GraphMLEdgeSource{filepath: filepath}
+	case "gml":
+		return GMLEdgeSource{filepath: filepath}
+	case "dot":
+		return DOTEdgeSource{filepath: filepath}
+	case "pajek":
+		return PajekEdgeSource{filepath: filepath}
+	case "matrixmarket":
+		return MatrixMarketEdgeSource{filepath: filepath}
+	case "adjacency":
+		return AdjacencyListEdgeSource{filepath: filepath, neighbourSeparator: options.NeighbourSeparator}
+	case "metis":
+		return AdjacencyListEdgeSource{filepath: filepath, metisHeader: true, directed: options.Directed}
+	}
+
+	log.Fatalf("[!] Unknown input format: %v\n", format)
+	return nil
+}
+
+// rereadEdges reads the edges of the input again after the connected components have been
+// calculated, normalising the entity IDs in the same way and skipping any invalid rows, which have
+// already been reported
+func rereadEdges(params Parameters, handleEdge func(entityID1 string, entityID2 string)) {
+	rereadEdgeRows(params, func(lineNumber int, entityID1 string, entityID2 string, weight float64) {
+		handleEdge(entityID1, entityID2)
+	})
+}
+
+// rereadEdgeRows reads the edges of the input again in the same way as rereadEdges, passing the
+// line or row number and the weight of each edge as well
+func rereadEdgeRows(params Parameters, handleEdge func(lineNumber int, entityID1 string, entityID2 string, weight float64)) {
+
+	handleRow := func(lineNumber int, row []string, weightText string) {
+
+		if len(validateRow(row)) > 0 {
+			return
+		}
+
+		weight, reason := parseEdgeWeight(weightText)
+		if len(reason) > 0 {
+			return
+		}
+
+		entityID1 := params.Normaliser.Normalise(row[0])
+		entityID2 := params.Normaliser.Normalise(row[1])
+		if len(entityID1) == 0 || len(entityID2) == 0 {
+			return
+		}
+
+		handleEdge(lineNumber, entityID1, entityID2, weight)
+	}
+
+	source := newEdgeSource(params.InputFilepath, inputFormat(params.InputFilepath, params.InputFormat), params.ReadOptions)
+	source.ReadEdges(EdgeHandlers{
+		Edge:       handleRow,
+		Vertex:     func(int, string) {},
+		ParseError: func(int, string, string) {},
+	})
+}
+
+// CSVEdgeSource reads a CSV edge list with the source and target entity IDs in the first two fields,
+// and the weight of each edge in the field with the given number, counting from 1, if it isn't 0
+type CSVEdgeSource struct {
+	filepath    string
+	weightField int
+}
+
+// csvWeightField converts the weight column of a CSV edge list, the number of its field counting
+// from 1, which must come after the source and target fields. A blank column gives 0, no weights.
+func csvWeightField(weightColumn string) int {
+
+	if len(weightColumn) == 0 {
+		return 0
+	}
+
+	field, err := strconv.Atoi(weightColumn)
+	if err != nil || field < 3 {
+		log.Fatalf("[!] The weight column of a CSV edge list must be the number of a field after the source and target fields, such as 3, found %v\n", weightColumn)
+	}
+
+	return field
+}
+
+// csvEdgeWeight takes the weight out of a row of a CSV edge list, leaving the other fields. A row
+// without the weight field has no weight.
+func csvEdgeWeight(row []string, weightField int) ([]string, string) {
+
+	if weightField == 0 || len(row) < weightField {
+		return row, ""
+	}
+
+	weight := row[weightField-1]
+	return append(append([]string{}, row[:weightField-1]...), row[weightField:]...), weight
+}
+
+// ReadEdges reads the rows of the CSV file
+func (s CSVEdgeSource) ReadEdges(handlers EdgeHandlers) {
+	readCSVEdges(s.filepath,
+		func(lineNumber int, row []string) {
+			row, weight := csvEdgeWeight(row, s.weightField)
+			handlers.Edge(lineNumber, row, weight)
+		},
+		handlers.ParseError)
+}
+
+// ParquetEdgeSource reads the source, target and optional weight columns of a Parquet file
+type ParquetEdgeSource struct {
+	filepath     string
+	sourceColumn string
+	targetColumn string
+	weightColumn string
+}
+
+// ReadEdges reads the Parquet file a row group at a time
+func (s ParquetEdgeSource) ReadEdges(handlers EdgeHandlers) {
+	readParquetEdges(s.filepath, s.sourceColumn, s.targetColumn, s.weightColumn, handlers.Edge)
+}
+
+// ArrowEdgeSource reads the source, target and optional weight columns of an Arrow IPC file or stream
+type ArrowEdgeSource struct {
+	filepath     string
+	sourceColumn string
+	targetColumn string
+	weightColumn string
+}
+
+// ReadEdges reads the Arrow input a record batch at a time
+func (s ArrowEdgeSource) ReadEdges(handlers EdgeHandlers) {
+	readArrowEdges(s.filepath, s.sourceColumn, s.targetColumn, s.weightColumn, handlers.Edge)
+}
+
+// SQLiteEdgeSource reads the source, target and optional weight columns of the results of a SQLite query
+type SQLiteEdgeSource struct {
+	filepath     string
+	query        string
+	sourceColumn string
+	targetColumn string
+	weightColumn string
+}
+
+// ReadEdges runs the query and reads its results
+func (s SQLiteEdgeSource) ReadEdges(handlers EdgeHandlers) {
+	readSQLiteEdges(s.filepath, s.query, s.sourceColumn, s.targetColumn, s.weightColumn, handlers.Edge)
+}
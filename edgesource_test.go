@@ -0,0 +1,166 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// readSource reads an edge source, returning the edges, with the weight after the entity IDs of
+// each edge that has one, declared vertices and parse errors
+func readSource(source EdgeSource) ([][]string, []string, []int) {
+
+	edges := [][]string{}
+	vertices := []string{}
+	parseErrors := []int{}
+
+	source.ReadEdges(EdgeHandlers{
+		Edge: func(lineNumber int, row []string, weight string) {
+			if len(weight) > 0 {
+				row = append(row, weight)
+			}
+			edges = append(edges, row)
+		},
+		Vertex:     func(lineNumber int, entityID string) { vertices = append(vertices, entityID) },
+		ParseError: func(lineNumber int, text string, reason string) { parseErrors = append(parseErrors, lineNumber) },
+	})
+
+	return edges, vertices, parseErrors
+}
+
+func TestCalculateConnectedComponentsGraphFormats(t *testing.T) {
+
+	// The same graph, with a self-loop and a vertex without edges, in each graph format
+	for _, filename := range []string{"graph.graphml", "graph.gml", "graph.dot", "graph.net", "graph.mtx", "graph.adj", "graph.metis"} {
+
+		calculateConnectedComponents("./test/test-5/"+filename, "./test/test-5/actual.csv", ",")
+
+		if !FilesHaveSameContent("./test/test-5/actual.csv", "./test/test-5/expected.csv") {
+			t.Fatalf("Expected %v and actual results don't match\n", filename)
+		}
+	}
+}
+
+func TestNewEdgeSource(t *testing.T) {
+	sources := map[string]EdgeSource{
+		"edges.csv":     CSVEdgeSource{filepath: "edges.csv"},
+		"edges.graphml": GraphMLEdgeSource{filepath: "edges.graphml"},
+		"edges.gml":     GMLEdgeSource{filepath: "edges.gml"},
+		"edges.gv":      DOTEdgeSource{filepath: "edges.gv"},
+		"edges.net":     PajekEdgeSource{filepath: "edges.net"},
+		"edges.mtx":     MatrixMarketEdgeSource{filepath: "edges.mtx"},
+		"edges.adj":     AdjacencyListEdgeSource{filepath: "edges.adj"},
+		"edges.metis":   AdjacencyListEdgeSource{filepath: "edges.metis", metisHeader: true},
+	}
+
+	for filepath, expected := range sources {
+		actual := newEdgeSource(filepath, inputFormat(filepath, ""), ReadOptions{})
+
+		if !reflect.DeepEqual(expected, actual) {
+			t.Fatalf("Expected %v for %v, got %v\n", expected, filepath, actual)
+		}
+	}
+}
+
+func TestGMLEdgeSource(t *testing.T) {
+
+	edges, vertices, _ := readSource(GMLEdgeSource{filepath: "./test/test-5/graph.gml"})
+
+	expectedEdges := [][]string{{"1", "2"}, {"2", "3"}, {"4", "5"}, {"6", "6"}}
+
+	if !reflect.DeepEqual(expectedEdges, edges) {
+		t.Fatalf("Expected %v, got %v\n", expectedEdges, edges)
+	}
+
+	expectedVertices := []string{"1", "2", "3", "4", "5", "6", "7"}
+
+	if !reflect.DeepEqual(expectedVertices, vertices) {
+		t.Fatalf("Expected %v, got %v\n", expectedVertices, vertices)
+	}
+}
+
+func TestDOTEdgeSourceSubgraphs(t *testing.T) {
+
+	edges, vertices, _ := readSource(DOTEdgeSource{filepath: "./test/subgraphs.dot"})
+
+	// An edge between subgraphs connects every node of one to every node of the other, while a
+	// subgraph name on its own is a node
+	expectedEdges := [][]string{
+		{"a", "c"}, {"a", "d"}, {"b", "c"}, {"b", "d"},
+		{"s", "e f"},
+		{"g", "h"}, {"g", "i"},
+		{"x1", "-2.5"},
+	}
+
+	if !reflect.DeepEqual(expectedEdges, edges) {
+		t.Fatalf("Expected %v, got %v\n", expectedEdges, edges)
+	}
+
+	expectedVertices := []string{"a", "b", "c", "d", "h", "i", "node", "<b>j</b>"}
+
+	if !reflect.DeepEqual(expectedVertices, vertices) {
+		t.Fatalf("Expected %v, got %v\n", expectedVertices, vertices)
+	}
+}
+
+func TestPajekEdgeSourceLabelsAndMatrix(t *testing.T) {
+
+	edges, vertices, parseErrors := readSource(PajekEdgeSource{filepath: "./test/labels.net"})
+
+	// Vertices are identified by their labels, where they have one, and the matrix values are the
+	// weights of the edges
+	expectedEdges := [][]string{{"first vertex", "b", "1"}, {"b", "first vertex", "1"}, {"b", "4", "0.5"}}
+
+	if !reflect.DeepEqual(expectedEdges, edges) {
+		t.Fatalf("Expected %v, got %v\n", expectedEdges, edges)
+	}
+
+	expectedVertices := []string{"first vertex", "b", "c", "4"}
+
+	if !reflect.DeepEqual(expectedVertices, vertices) {
+		t.Fatalf("Expected %v, got %v\n", expectedVertices, vertices)
+	}
+
+	if !reflect.DeepEqual([]int{9}, parseErrors) {
+		t.Fatalf("Expected a parse error on line 9, got %v\n", parseErrors)
+	}
+}
+
+func TestMatrixMarketEdgeSourceOutOfRange(t *testing.T) {
+
+	edges, vertices, parseErrors := readSource(MatrixMarketEdgeSource{filepath: "./test/out-of-range.mtx"})
+
+	if !reflect.DeepEqual([][]string{{"1", "2"}}, edges) {
+		t.Fatalf("Expected one edge, got %v\n", edges)
+	}
+
+	if !reflect.DeepEqual([]string{"1", "2"}, vertices) {
+		t.Fatalf("Expected two vertices, got %v\n", vertices)
+	}
+
+	if !reflect.DeepEqual([]int{4, 5}, parseErrors) {
+		t.Fatalf("Expected parse errors on lines 4 and 5, got %v\n", parseErrors)
+	}
+}
+
+func TestCSVEdgeWeight(t *testing.T) {
+	rows := []struct {
+		row         []string
+		weightField int
+		expected    []string
+		weight      string
+	}{
+		{[]string{"a", "b", "2"}, 3, []string{"a", "b"}, "2"},
+		{[]string{"a", "b", "x", "2"}, 4, []string{"a", "b", "x"}, "2"},
+		{[]string{"a", "b", "2", "x"}, 3, []string{"a", "b", "x"}, "2"},
+		{[]string{"a", "b"}, 3, []string{"a", "b"}, ""},
+		{[]string{"a", "b", "2"}, 0, []string{"a", "b", "2"}, ""},
+	}
+
+	for _, r := range rows {
+		actual, weight := csvEdgeWeight(r.row, r.weightField)
+
+		if !reflect.DeepEqual(r.expected, actual) || r.weight != weight {
+			t.Fatalf("Expected %v and %q for %v, got %v and %q\n", r.expected, r.weight, r.row, actual, weight)
+		}
+	}
+}
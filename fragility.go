@@ -0,0 +1,382 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// UndirectedEdge is an edge between two vertices of an UndirectedGraph with the line or row number
+// of the input it was read from and its weight
+type UndirectedEdge struct {
+	vertex1    int
+	vertex2    int
+	lineNumber int
+	weight     float64
+}
+
+// UndirectedGraph holds the edges of an undirected graph with the vertices numbered in the order
+// they are first seen
+type UndirectedGraph struct {
+	vertexIndex map[string]int
+	vertices    []string
+	edges       []UndirectedEdge
+	incident    [][]int
+}
+
+// NewUndirectedGraph sets up a new empty UndirectedGraph
+func NewUndirectedGraph() *UndirectedGraph {
+	return &UndirectedGraph{
+		vertexIndex: map[string]int{},
+		vertices:    []string{},
+		edges:       []UndirectedEdge{},
+		incident:    [][]int{},
+	}
+}
+
+// AddVertex adds a vertex if it hasn't been seen before and returns its index
+func (g *UndirectedGraph) AddVertex(entityID string) int {
+
+	if index, present := g.vertexIndex[entityID]; present {
+		return index
+	}
+
+	index := len(g.vertices)
+	g.vertexIndex[entityID] = index
+	g.vertices = append(g.vertices, entityID)
+	g.incident = append(g.incident, nil)
+
+	return index
+}
+
+// AddEdge adds an edge of weight 1 read from a line of the input
+func (g *UndirectedGraph) AddEdge(pair EntityPair, lineNumber int) {
+	g.AddWeightedEdge(pair, lineNumber, 1)
+}
+
+// AddWeightedEdge adds an edge with a weight read from a line of the input, ignoring self-loops,
+// which can't split a graph
+func (g *UndirectedGraph) AddWeightedEdge(pair EntityPair, lineNumber int, weight float64) {
+
+	vertex1 := g.AddVertex(pair.EntityID1)
+	vertex2 := g.AddVertex(pair.EntityID2)
+	if vertex1 == vertex2 {
+		return
+	}
+
+	edge := len(g.edges)
+	g.edges = append(g.edges, UndirectedEdge{vertex1: vertex1, vertex2: vertex2, lineNumber: lineNumber, weight: weight})
+	g.incident[vertex1] = append(g.incident[vertex1], edge)
+	g.incident[vertex2] = append(g.incident[vertex2], edge)
+}
+
+// undirectedGraphFromInput reads the edges of the input again into an UndirectedGraph, with the
+// line or row number and the weight of each edge
+func undirectedGraphFromInput(params Parameters) *UndirectedGraph {
+
+	log.Printf("Reading the edges into memory ...\n")
+
+	graph := NewUndirectedGraph()
+	rereadEdgeRows(params, func(lineNumber int, entityID1 string, entityID2 string, weight float64) {
+		graph.AddWeightedEdge(EntityPair{EntityID1: entityID1, EntityID2: entityID2}, lineNumber, weight)
+	})
+
+	log.Printf("Read %v vertices and %v edges into memory\n", len(graph.vertices), len(graph.edges))
+
+	return graph
+}
+
+// otherVertex returns the vertex at the other end of an edge
+func (g *UndirectedGraph) otherVertex(edge int, vertex int) int {
+	if g.edges[edge].vertex1 == vertex {
+		return g.edges[edge].vertex2
+	}
+	return g.edges[edge].vertex1
+}
+
+// distinctNeighbours returns the neighbours of each vertex in order, counting repeated edges once
+func (g *UndirectedGraph) distinctNeighbours() [][]int {
+
+	neighbours := make([][]int, len(g.vertices))
+	for v, edges := range g.incident {
+		for _, edge := range edges {
+			neighbours[v] = append(neighbours[v], g.otherVertex(edge, v))
+		}
+		sort.Ints(neighbours[v])
+		distinct := neighbours[v][:0]
+		for i, w := range neighbours[v] {
+			if i == 0 || w != neighbours[v][i-1] {
+				distinct = append(distinct, w)
+			}
+		}
+		neighbours[v] = distinct
+	}
+
+	return neighbours
+}
+
+// Fragility holds the vertices and edges whose removal would split a connected component, and the
+// biconnected components of the graph, as vertex and edge indices
+type Fragility struct {
+	ArticulationPoints    []int
+	Bridges               []int
+	BiconnectedComponents [][]int
+}
+
+// fragilityFrame is a vertex being visited by the depth-first search with the edge it was reached
+// by and the position of the next incident edge to follow
+type fragilityFrame struct {
+	vertex       int
+	parentEdge   int
+	nextIncident int
+}
+
+// Fragility finds the articulation points, bridges and biconnected components with the
+// Hopcroft-Tarjan algorithm, using an explicit stack rather than recursion so that deep graphs
+// can't overflow the call stack. Parallel edges are followed separately, so they are never bridges.
+func (g *UndirectedGraph) Fragility() Fragility {
+
+	numberVertices := len(g.vertices)
+	discovery := make([]int, numberVertices)
+	lowLink := make([]int, numberVertices)
+	isArticulationPoint := make([]bool, numberVertices)
+
+	for v := range discovery {
+		discovery[v] = -1
+	}
+
+	fragility := Fragility{ArticulationPoints: []int{}, Bridges: []int{}, BiconnectedComponents: [][]int{}}
+	nextDiscovery := 0
+	edgeStack := []int{}
+	callStack := []fragilityFrame{}
+
+	// visit numbers a vertex and puts it on the call stack
+	visit := func(v int, parentEdge int) {
+		discovery[v] = nextDiscovery
+		lowLink[v] = nextDiscovery
+		nextDiscovery++
+		callStack = append(callStack, fragilityFrame{vertex: v, parentEdge: parentEdge})
+	}
+
+	// popBiconnectedComponent takes the edges of a biconnected component off the edge stack, down to
+	// and including the edge it was entered by, and records its vertices
+	popBiconnectedComponent := func(lastEdge int) {
+		seen := map[int]struct{}{}
+		members := []int{}
+		for {
+			edge := edgeStack[len(edgeStack)-1]
+			edgeStack = edgeStack[:len(edgeStack)-1]
+			for _, v := range []int{g.edges[edge].vertex1, g.edges[edge].vertex2} {
+				if _, present := seen[v]; !present {
+					seen[v] = struct{}{}
+					members = append(members, v)
+				}
+			}
+			if edge == lastEdge {
+				break
+			}
+		}
+		fragility.BiconnectedComponents = append(fragility.BiconnectedComponents, members)
+	}
+
+	for root := 0; root < numberVertices; root++ {
+
+		if discovery[root] >= 0 {
+			continue
+		}
+
+		visit(root, -1)
+		rootChildren := 0
+
+		for len(callStack) > 0 {
+			frame := &callStack[len(callStack)-1]
+			v := frame.vertex
+
+			// Follow the next edge, to a new vertex or back to an ancestor
+			if frame.nextIncident < len(g.incident[v]) {
+				edge := g.incident[v][frame.nextIncident]
+				frame.nextIncident++
+
+				if edge == frame.parentEdge {
+					continue
+				}
+
+				w := g.otherVertex(edge, v)
+				if discovery[w] < 0 {
+					edgeStack = append(edgeStack, edge)
+					if v == root {
+						rootChildren++
+					}
+					visit(w, edge)
+				} else if discovery[w] < discovery[v] {
+					edgeStack = append(edgeStack, edge)
+					lowLink[v] = min(lowLink[v], discovery[w])
+				}
+				continue
+			}
+
+			// All edges have been followed, so check whether the edge to the parent holds the
+			// subtree on
+			callStack = callStack[:len(callStack)-1]
+
+			if len(callStack) == 0 {
+				continue
+			}
+
+			parent := callStack[len(callStack)-1].vertex
+			lowLink[parent] = min(lowLink[parent], lowLink[v])
+
+			if lowLink[v] > discovery[parent] {
+				fragility.Bridges = append(fragility.Bridges, frame.parentEdge)
+			}
+
+			if lowLink[v] >= discovery[parent] {
+				if parent != root {
+					isArticulationPoint[parent] = true
+				}
+				popBiconnectedComponent(frame.parentEdge)
+			}
+		}
+
+		if rootChildren > 1 {
+			isArticulationPoint[root] = true
+		}
+	}
+
+	for v, articulationPoint := range isArticulationPoint {
+		if articulationPoint {
+			fragility.ArticulationPoints = append(fragility.ArticulationPoints, v)
+		}
+	}
+
+	return fragility
+}
+
+// articulationPointsHeader builds the header of the articulation points file
+func articulationPointsHeader(delimiter string) string {
+
+	// Precondition
+	if len(delimiter) == 0 {
+		log.Fatal("Cannot use a blank delimiter")
+	}
+
+	return "Entity ID" + delimiter + "Component ID"
+}
+
+// bridgesHeader builds the header of the bridges file
+func bridgesHeader(delimiter string) string {
+
+	// Precondition
+	if len(delimiter) == 0 {
+		log.Fatal("Cannot use a blank delimiter")
+	}
+
+	return strings.Join([]string{"Line Number", "Entity ID", "Target Entity ID", "Component ID"}, delimiter)
+}
+
+// biconnectedComponentsHeader builds the header of the biconnected components file
+func biconnectedComponentsHeader(delimiter string) string {
+
+	// Precondition
+	if len(delimiter) == 0 {
+		log.Fatal("Cannot use a blank delimiter")
+	}
+
+	return strings.Join([]string{"Biconnected Component ID", "Component ID", "Size", "Members"}, delimiter)
+}
+
+// createFragilityFile creates one of the fragility files in the fragility directory and writes its
+// header
+func createFragilityFile(directory string, filename string, header string) (*os.File, *bufio.Writer) {
+
+	filepath := path.Join(directory, filename)
+	outputFile, err := os.Create(filepath)
+	if err != nil {
+		log.Fatalf("[!] Unable to open fragility file %v for writing: %v\n", filepath, err)
+	}
+
+	bufferedWriter := bufio.NewWriter(outputFile)
+	fmt.Fprintln(bufferedWriter, header)
+
+	return outputFile, bufferedWriter
+}
+
+// writeFragilityFiles writes the articulation points, bridges and biconnected components of each
+// connected component to separate files in the fragility directory. The bridges are given with
+// their line or row numbers so that they can be traced back to the input.
+func writeFragilityFiles(cc *ConnectedComponents, graph *UndirectedGraph, params Parameters) {
+
+	log.Printf("Writing articulation points, bridges and biconnected components to directory %v ...\n", params.FragilityDirectory)
+
+	if err := os.MkdirAll(params.FragilityDirectory, 0755); err != nil {
+		log.Fatalf("[!] Unable to create fragility directory %v: %v\n", params.FragilityDirectory, err)
+	}
+
+	fragility := graph.Fragility()
+	delimiter := params.OutputDelimiter
+
+	// Articulation points, sorted by component and entity ID
+	articulationPoints := make([]string, len(fragility.ArticulationPoints))
+	for i, v := range fragility.ArticulationPoints {
+		articulationPoints[i] = graph.vertices[v]
+	}
+	sort.Slice(articulationPoints, func(i, j int) bool {
+		component1 := cc.vertexToConnectedComponent[articulationPoints[i]]
+		component2 := cc.vertexToConnectedComponent[articulationPoints[j]]
+		if component1 != component2 {
+			return component1 < component2
+		}
+		return articulationPoints[i] < articulationPoints[j]
+	})
+
+	outputFile, bufferedWriter := createFragilityFile(params.FragilityDirectory, "articulation-points.csv", articulationPointsHeader(delimiter))
+	for _, entityID := range articulationPoints {
+		fmt.Fprintln(bufferedWriter, entityID+delimiter+strconv.Itoa(cc.vertexToConnectedComponent[entityID]))
+	}
+	bufferedWriter.Flush()
+	outputFile.Close()
+
+	// Bridges, in the order of the input
+	sort.Ints(fragility.Bridges)
+
+	outputFile, bufferedWriter = createFragilityFile(params.FragilityDirectory, "bridges.csv", bridgesHeader(delimiter))
+	for _, edge := range fragility.Bridges {
+		entityID1 := graph.vertices[graph.edges[edge].vertex1]
+		entityID2 := graph.vertices[graph.edges[edge].vertex2]
+		fmt.Fprintln(bufferedWriter, strings.Join([]string{
+			strconv.Itoa(graph.edges[edge].lineNumber),
+			entityID1,
+			entityID2,
+			strconv.Itoa(cc.vertexToConnectedComponent[entityID1]),
+		}, delimiter))
+	}
+	bufferedWriter.Flush()
+	outputFile.Close()
+
+	// Biconnected components, numbered in the order they were found, with their sorted members
+	outputFile, bufferedWriter = createFragilityFile(params.FragilityDirectory, "biconnected-components.csv", biconnectedComponentsHeader(delimiter))
+	for i, vertices := range fragility.BiconnectedComponents {
+		members := make([]string, len(vertices))
+		for j, v := range vertices {
+			members[j] = graph.vertices[v]
+		}
+		sort.Strings(members)
+
+		fmt.Fprintln(bufferedWriter, strings.Join([]string{
+			strconv.Itoa(i),
+			strconv.Itoa(cc.vertexToConnectedComponent[members[0]]),
+			strconv.Itoa(len(members)),
+			strings.Join(members, params.MemberDelimiter),
+		}, delimiter))
+	}
+	bufferedWriter.Flush()
+	outputFile.Close()
+
+	log.Printf("Found %v articulation points, %v bridges and %v biconnected components\n",
+		len(fragility.ArticulationPoints), len(fragility.Bridges), len(fragility.BiconnectedComponents))
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestWriteFragilityFiles(t *testing.T) {
+
+	// Two cycles joined by a bridge with a bridge hanging off the second, a parallel edge, which is
+	// never a bridge, and a self-loop, which is ignored
+	params := NewParameters("./test/test-8/edge_list.csv", "./test/test-8/actual.csv", ",")
+	params.FragilityDirectory = "./test/test-8/actual-fragility"
+	os.RemoveAll(params.FragilityDirectory)
+	calculateConnectedComponentsWithParameters(params)
+
+	expected := map[string]string{
+		"articulation-points.csv": "Entity ID,Component ID\nc,0\nd,0\nf,0\n",
+		"bridges.csv":             "Line Number,Entity ID,Target Entity ID,Component ID\n4,c,d,0\n8,f,g,0\n",
+		"biconnected-components.csv": "Biconnected Component ID,Component ID,Size,Members\n" +
+			"0,0,2,f|g\n1,0,3,d|e|f\n2,0,2,c|d\n3,0,3,a|b|c\n4,1,2,h|i\n",
+	}
+
+	if actual := readSplitDirectory(t, params.FragilityDirectory); !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected %v, got %v\n", expected, actual)
+	}
+}
+
+func TestFragilityDeepGraph(t *testing.T) {
+
+	// A path of a million vertices would overflow a recursive depth-first search
+	numberVertices := 1000000
+	graph := NewUndirectedGraph()
+	for i := 1; i < numberVertices; i++ {
+		graph.AddEdge(EntityPair{EntityID1: strconv.Itoa(i - 1), EntityID2: strconv.Itoa(i)}, i)
+	}
+
+	fragility := graph.Fragility()
+
+	if len(fragility.Bridges) != numberVertices-1 {
+		t.Fatalf("Expected %v bridges, got %v\n", numberVertices-1, len(fragility.Bridges))
+	}
+
+	if len(fragility.ArticulationPoints) != numberVertices-2 {
+		t.Fatalf("Expected %v articulation points, got %v\n", numberVertices-2, len(fragility.ArticulationPoints))
+	}
+
+	if len(fragility.BiconnectedComponents) != numberVertices-1 {
+		t.Fatalf("Expected %v biconnected components, got %v\n", numberVertices-1, len(fragility.BiconnectedComponents))
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"log"
+	"os"
+	"strings"
+)
+
+// GMLEdgeSource reads the nodes and edges of a GML (Graph Modelling Language) file
+type GMLEdgeSource struct {
+	filepath string
+}
+
+// gmlToken is a key, value or bracket of a GML file with the line it starts on
+type gmlToken struct {
+	text       string
+	lineNumber int
+}
+
+// gmlLexer splits a GML file into tokens, skipping comments
+type gmlLexer struct {
+	r          *bufio.Reader
+	lineNumber int
+}
+
+// readRune reads the next character, counting lines
+func (l *gmlLexer) readRune() (rune, bool) {
+
+	c, _, err := l.r.ReadRune()
+	if err == io.EOF {
+		return 0, false
+	}
+	if err != nil {
+		log.Fatal("[!] Error reading GML file: ", err)
+	}
+
+	if c == '\n' {
+		l.lineNumber++
+	}
+
+	return c, true
+}
+
+// next returns the next token and false at the end of the file. Quoted strings are returned with
+// their quotes so that they can be told apart from keys and brackets.
+func (l *gmlLexer) next() (gmlToken, bool) {
+
+	for {
+		c, ok := l.readRune()
+		if !ok {
+			return gmlToken{}, false
+		}
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			continue
+
+		case c == '#':
+			// A comment runs to the end of the line
+			for c != '\n' {
+				if c, ok = l.readRune(); !ok {
+					return gmlToken{}, false
+				}
+			}
+
+		case c == '[' || c == ']':
+			return gmlToken{text: string(c), lineNumber: l.lineNumber}, true
+
+		case c == '"':
+			// A string runs to the closing quote and may span lines
+			token := gmlToken{lineNumber: l.lineNumber}
+			var text strings.Builder
+			text.WriteRune(c)
+			for {
+				if c, ok = l.readRune(); !ok {
+					log.Fatalf("[!] Error parsing GML file: unterminated string starting at line %v\n", token.lineNumber)
+				}
+				text.WriteRune(c)
+				if c == '"' {
+					break
+				}
+			}
+			token.text = text.String()
+			return token, true
+
+		default:
+			// A key or number runs to the next space or bracket
+			token := gmlToken{lineNumber: l.lineNumber}
+			var text strings.Builder
+			text.WriteRune(c)
+			for {
+				c, ok = l.readRune()
+				if !ok {
+					break
+				}
+				if c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '[' || c == ']' {
+					l.r.UnreadRune()
+					if c == '\n' {
+						l.lineNumber--
+					}
+					break
+				}
+				text.WriteRune(c)
+			}
+			token.text = text.String()
+			return token, true
+		}
+	}
+}
+
+// gmlList is an open list of a GML file and the node or edge fields read from it
+type gmlList struct {
+	key        string
+	lineNumber int
+	id         string
+	source     string
+	target     string
+}
+
+// gmlValue returns a scalar GML value with the quotes removed from strings and HTML quote entities
+// decoded
+func gmlValue(text string) string {
+	if strings.HasPrefix(text, `"`) {
+		return strings.ReplaceAll(text[1:len(text)-1], "&quot;", `"`)
+	}
+	return text
+}
+
+// ReadEdges reads the GML file, passing the id of each node of a graph as a vertex and the source
+// and target of each edge of a graph as an edge. Other keys are ignored.
+func (s GMLEdgeSource) ReadEdges(handlers EdgeHandlers) {
+
+	// Open the file for reading and ensure it is closed
+	file, err := os.Open(s.filepath)
+	if err != nil {
+		log.Fatal("[!] Couldn't open GML file ", err)
+	}
+	defer file.Close()
+
+	lexer := gmlLexer{r: bufio.NewReader(file), lineNumber: 1}
+
+	// Stack of the open lists, starting with the top level of the file
+	lists := []gmlList{{}}
+
+	for {
+		key, ok := lexer.next()
+		if !ok {
+			break
+		}
+
+		if key.text == "]" {
+			if len(lists) == 1 {
+				log.Fatalf("[!] Error parsing GML file: unexpected ] at line %v\n", key.lineNumber)
+			}
+
+			list := lists[len(lists)-1]
+			lists = lists[:len(lists)-1]
+
+			if lists[len(lists)-1].key == "graph" {
+				switch list.key {
+				case "node":
+					handlers.Vertex(list.lineNumber, list.id)
+				case "edge":
+					handlers.Edge(list.lineNumber, []string{list.source, list.target}, "")
+				}
+			}
+			continue
+		}
+
+		if key.text == "[" || strings.HasPrefix(key.text, `"`) {
+			log.Fatalf("[!] Error parsing GML file: expected a key at line %v but found %v\n", key.lineNumber, key.text)
+		}
+
+		value, ok := lexer.next()
+		if !ok || value.text == "]" {
+			log.Fatalf("[!] Error parsing GML file: missing value of key %v at line %v\n", key.text, key.lineNumber)
+		}
+
+		if value.text == "[" {
+			lists = append(lists, gmlList{key: key.text, lineNumber: key.lineNumber})
+			continue
+		}
+
+		list := &lists[len(lists)-1]
+		switch key.text {
+		case "id":
+			list.id = gmlValue(value.text)
+		case "source":
+			list.source = gmlValue(value.text)
+		case "target":
+			list.target = gmlValue(value.text)
+		}
+	}
+
+	if len(lists) > 1 {
+		log.Fatalf("[!] Error parsing GML file: list %v starting at line %v is not closed\n",
+			lists[len(lists)-1].key, lists[len(lists)-1].lineNumber)
+	}
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"strings"
+)
+
+// GraphWriter writes the graph annotated with its connected components in a graph format. The
+// components are given in order with their sorted members, followed by the edges.
+type GraphWriter interface {
+	WriteHeader()
+	WriteComponent(component int, members []string)
+	WriteEdge(entityID1 string, entityID2 string)
+	WriteFooter()
+}
+
+// graphOutputFormat returns the format of the annotated graph file, given explicitly or by its
+// extension
+func graphOutputFormat(filepath string, format string) string {
+
+	if len(format) > 0 {
+		return format
+	}
+
+	switch strings.ToLower(path.Ext(filepath)) {
+	case ".gexf":
+		return "gexf"
+	case ".dot", ".gv":
+		return "dot"
+	}
+
+	return "graphml"
+}
+
+// newGraphWriter sets up the GraphWriter for a graph format, with directed or undirected edges
+func newGraphWriter(format string, w io.Writer, directed bool) GraphWriter {
+
+	switch format {
+	case "graphml":
+		return &GraphMLWriter{w: w, directed: directed}
+	case "gexf":
+		return &GEXFWriter{w: w, directed: directed}
+	case "dot":
+		return &DOTWriter{w: w, directed: directed}
+	}
+
+	log.Fatalf("[!] Unknown graph output format: %v\n", format)
+	return nil
+}
+
+// xmlEscape escapes a string for use in XML text or a double-quoted attribute
+func xmlEscape(s string) string {
+	var escaped strings.Builder
+	xml.EscapeText(&escaped, []byte(s))
+	return escaped.String()
+}
+
+// edgeType returns the name of the type of the edges in the GraphML and GEXF formats
+func edgeType(directed bool) string {
+	if directed {
+		return "directed"
+	}
+	return "undirected"
+}
+
+// GraphMLWriter writes a GraphML file with component_id and component_size node attributes
+type GraphMLWriter struct {
+	w        io.Writer
+	directed bool
+}
+
+// WriteHeader writes the attribute keys and opens the graph
+func (g *GraphMLWriter) WriteHeader() {
+	fmt.Fprintln(g.w, `<?xml version="1.0" encoding="UTF-8"?>`)
+	fmt.Fprintln(g.w, `<graphml xmlns="http://graphml.graphdrawing.org/xmlns">`)
+	fmt.Fprintln(g.w, `  <key id="component_id" for="node" attr.name="component_id" attr.type="int"/>`)
+	fmt.Fprintln(g.w, `  <key id="component_size" for="node" attr.name="component_size" attr.type="int"/>`)
+	fmt.Fprintf(g.w, `  <graph id="G" edgedefault="%v">`+"\n", edgeType(g.directed))
+}
+
+// WriteComponent writes a node element for each member of a component
+func (g *GraphMLWriter) WriteComponent(component int, members []string) {
+	for _, member := range members {
+		fmt.Fprintf(g.w, `    <node id="%v"><data key="component_id">%v</data><data key="component_size">%v</data></node>`+"\n",
+			xmlEscape(member), component, len(members))
+	}
+}
+
+// WriteEdge writes an edge element
+func (g *GraphMLWriter) WriteEdge(entityID1 string, entityID2 string) {
+	fmt.Fprintf(g.w, `    <edge source="%v" target="%v"/>`+"\n", xmlEscape(entityID1), xmlEscape(entityID2))
+}
+
+// WriteFooter closes the graph
+func (g *GraphMLWriter) WriteFooter() {
+	fmt.Fprintln(g.w, `  </graph>`)
+	fmt.Fprintln(g.w, `</graphml>`)
+}
+
+// GEXFWriter writes a GEXF file with component_id and component_size node attributes
+type GEXFWriter struct {
+	w             io.Writer
+	directed      bool
+	numberEdges   int
+	nodesFinished bool
+}
+
+// WriteHeader writes the attribute declarations and opens the nodes
+func (g *GEXFWriter) WriteHeader() {
+	fmt.Fprintln(g.w, `<?xml version="1.0" encoding="UTF-8"?>`)
+	fmt.Fprintln(g.w, `<gexf xmlns="http://www.gexf.net/1.2draft" version="1.2">`)
+	fmt.Fprintf(g.w, `  <graph mode="static" defaultedgetype="%v">`+"\n", edgeType(g.directed))
+	fmt.Fprintln(g.w, `    <attributes class="node">`)
+	fmt.Fprintln(g.w, `      <attribute id="component_id" title="component_id" type="integer"/>`)
+	fmt.Fprintln(g.w, `      <attribute id="component_size" title="component_size" type="integer"/>`)
+	fmt.Fprintln(g.w, `    </attributes>`)
+	fmt.Fprintln(g.w, `    <nodes>`)
+}
+
+// WriteComponent writes a node element for each member of a component
+func (g *GEXFWriter) WriteComponent(component int, members []string) {
+	for _, member := range members {
+		escaped := xmlEscape(member)
+		fmt.Fprintf(g.w, `      <node id="%v" label="%v"><attvalues><attvalue for="component_id" value="%v"/>`+
+			`<attvalue for="component_size" value="%v"/></attvalues></node>`+"\n", escaped, escaped, component, len(members))
+	}
+}
+
+// finishNodes closes the nodes and opens the edges, once
+func (g *GEXFWriter) finishNodes() {
+	if !g.nodesFinished {
+		fmt.Fprintln(g.w, `    </nodes>`)
+		fmt.Fprintln(g.w, `    <edges>`)
+		g.nodesFinished = true
+	}
+}
+
+// WriteEdge writes an edge element, numbering the edges in order
+func (g *GEXFWriter) WriteEdge(entityID1 string, entityID2 string) {
+	g.finishNodes()
+	fmt.Fprintf(g.w, `      <edge id="%v" source="%v" target="%v"/>`+"\n", g.numberEdges, xmlEscape(entityID1),
+		xmlEscape(entityID2))
+	g.numberEdges++
+}
+
+// WriteFooter closes the edges and the graph
+func (g *GEXFWriter) WriteFooter() {
+	g.finishNodes()
+	fmt.Fprintln(g.w, `    </edges>`)
+	fmt.Fprintln(g.w, `  </graph>`)
+	fmt.Fprintln(g.w, `</gexf>`)
+}
+
+// DOTWriter writes a Graphviz DOT file with each component grouped into a cluster subgraph
+type DOTWriter struct {
+	w        io.Writer
+	directed bool
+}
+
+// dotQuote quotes an entity ID as a DOT ID, escaping backslashes so that an ID ending in one
+// doesn't escape the closing quote
+func dotQuote(entityID string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(entityID) + `"`
+}
+
+// WriteHeader opens the graph
+func (d *DOTWriter) WriteHeader() {
+	if d.directed {
+		fmt.Fprintln(d.w, "digraph components {")
+	} else {
+		fmt.Fprintln(d.w, "graph components {")
+	}
+}
+
+// WriteComponent writes a cluster subgraph holding the members of a component
+func (d *DOTWriter) WriteComponent(component int, members []string) {
+	fmt.Fprintf(d.w, "  subgraph cluster_%v {\n", component)
+	fmt.Fprintf(d.w, "    label=\"Component %v, size %v\";\n", component, len(members))
+	for _, member := range members {
+		fmt.Fprintf(d.w, "    %v;\n", dotQuote(member))
+	}
+	fmt.Fprintln(d.w, "  }")
+}
+
+// WriteEdge writes an edge, from the first entity to the second in a directed graph
+func (d *DOTWriter) WriteEdge(entityID1 string, entityID2 string) {
+	edgeOperator := "--"
+	if d.directed {
+		edgeOperator = "->"
+	}
+	fmt.Fprintf(d.w, "  %v %v %v;\n", dotQuote(entityID1), edgeOperator, dotQuote(entityID2))
+}
+
+// WriteFooter closes the graph
+func (d *DOTWriter) WriteFooter() {
+	fmt.Fprintln(d.w, "}")
+}
+
+// writeAnnotatedGraph writes the graph with the connected component of each vertex to a GraphML,
+// GEXF or DOT file. The edges are written as they are read again from the input, rather than held
+// in memory, skipping any invalid rows.
+func writeAnnotatedGraph(cc *ConnectedComponents, params Parameters) {
+
+	format := graphOutputFormat(params.GraphOutputFilepath, params.GraphOutputFormat)
+	log.Printf("Writing annotated graph to %v file %v ...\n", format, params.GraphOutputFilepath)
+
+	// Open the output file for writing
+	outputFile, err := os.Create(params.GraphOutputFilepath)
+	if err != nil {
+		log.Fatalf("[!] Unable to open graph output file %v for writing: %v\n", params.GraphOutputFilepath, err)
+	}
+	defer outputFile.Close()
+
+	bufferedWriter := bufio.NewWriter(outputFile)
+	defer bufferedWriter.Flush()
+
+	writer := newGraphWriter(format, bufferedWriter, params.Directed)
+	writer.WriteHeader()
+
+	// Write the vertices grouped by connected component
+	for _, component := range *sortedListComponents(&cc.connectedComponentToVertices) {
+		writer.WriteComponent(component, sortedMembers(cc.connectedComponentToVertices[component]))
+	}
+
+	// Write the edges read from the input, as normalised when the components were calculated
+	numberEdgesWritten := 0
+	rereadEdges(params, func(entityID1 string, entityID2 string) {
+		writer.WriteEdge(entityID1, entityID2)
+		numberEdgesWritten++
+
+		if numberEdgesWritten%1000000 == 0 {
+			log.Printf("Number of edges written to graph file: %v\n", numberEdgesWritten)
+		}
+	})
+
+	writer.WriteFooter()
+
+	log.Printf("Wrote %v vertices and %v edges to graph file %v\n", len(cc.vertexToConnectedComponent),
+		numberEdgesWritten, params.GraphOutputFilepath)
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWriteAnnotatedGraph(t *testing.T) {
+
+	// Annotated graphs of a file with a self-loop, declared vertices and an escaped entity ID
+	for _, extension := range []string{"graphml", "gexf", "dot"} {
+
+		params := NewParameters("./test/test-6/edge_list.csv", "./test/test-6/actual.csv", ",")
+		params.VertexFilepath = "./test/test-6/vertices.csv"
+		params.GraphOutputFilepath = "./test/test-6/actual." + extension
+		calculateConnectedComponentsWithParameters(params)
+
+		if !FilesHaveSameContent(params.GraphOutputFilepath, "./test/test-6/expected."+extension) {
+			t.Fatalf("Expected and actual %v graphs differ\n", extension)
+		}
+	}
+}
+
+func TestWriteAnnotatedDirectedGraph(t *testing.T) {
+
+	// With -directed the edges keep their direction and the vertices are grouped by strongly
+	// connected component
+	for _, extension := range []string{"graphml", "gexf", "dot"} {
+
+		params := NewParameters("./test/test-7/edge_list.csv", "./test/test-7/actual.csv", ",")
+		params.Directed = true
+		params.GraphOutputFilepath = "./test/test-7/actual." + extension
+		calculateConnectedComponentsWithParameters(params)
+
+		if !FilesHaveSameContent(params.GraphOutputFilepath, "./test/test-7/expected."+extension) {
+			t.Fatalf("Expected and actual directed %v graphs differ\n", extension)
+		}
+	}
+}
+
+func TestDOTQuoteRoundTrip(t *testing.T) {
+
+	// Quotes and backslashes in the entity IDs of a written DOT file are read back unchanged
+	edges, _, _ := readSource(DOTEdgeSource{filepath: "./test/test-6/expected.dot"})
+	expected := [][]string{{"a", "b"}, {"b", `c "<q>" & d`}, {"e", "e"}, {"f", "a"}, {"f", `h\i\`}}
+
+	if !reflect.DeepEqual(expected, edges) {
+		t.Fatalf("Expected %v, got %v\n", expected, edges)
+	}
+}
+
+func TestWriteAnnotatedGraphRoundTrip(t *testing.T) {
+
+	// Reading an annotated graph gives the same connected components as the original edge list
+	for _, extension := range []string{"graphml", "dot"} {
+
+		params := NewParameters("./test/test-2/edge_list.csv", "./test/test-2/actual.csv", ",")
+		params.GraphOutputFilepath = "./test/test-2/actual." + extension
+		calculateConnectedComponentsWithParameters(params)
+
+		calculateConnectedComponents(params.GraphOutputFilepath, "./test/test-2/actual-round-trip.csv", ",")
+
+		if !FilesHaveSameContent("./test/test-2/actual-round-trip.csv", "./test/test-2/expected.csv") {
+			t.Fatalf("Expected and actual results of reading the %v graph differ\n", extension)
+		}
+	}
+}
+
+func TestGraphOutputFormat(t *testing.T) {
+	formats := map[string]string{
+		"graph.graphml": "graphml",
+		"graph.xml":     "graphml",
+		"graph.GEXF":    "gexf",
+		"graph.gv":      "dot",
+	}
+
+	for filepath, expected := range formats {
+		if actual := graphOutputFormat(filepath, ""); actual != expected {
+			t.Fatalf("Expected %v for %v, got %v\n", expected, filepath, actual)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bufio"
+	"encoding/xml"
+	"io"
+	"log"
+	"os"
+)
+
+// GraphMLEdgeSource reads the nodes and edges of a GraphML file
+type GraphMLEdgeSource struct {
+	filepath string
+}
+
+// xmlAttribute returns the value of an attribute of an element, or a blank string if it is missing
+func xmlAttribute(element xml.StartElement, name string) string {
+	for _, attribute := range element.Attr {
+		if attribute.Name.Local == name {
+			return attribute.Value
+		}
+	}
+	return ""
+}
+
+// ReadEdges streams the GraphML file, passing each node element as a vertex and each edge element
+// as an edge. Data keys and hyperedges are ignored.
+func (s GraphMLEdgeSource) ReadEdges(handlers EdgeHandlers) {
+
+	// Open the file for reading and ensure it is closed
+	file, err := os.Open(s.filepath)
+	if err != nil {
+		log.Fatal("[!] Couldn't open GraphML file ", err)
+	}
+	defer file.Close()
+
+	decoder := xml.NewDecoder(bufio.NewReader(file))
+
+	for {
+		token, err := decoder.Token()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			log.Fatal("[!] Error reading GraphML file: ", err)
+		}
+
+		element, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		lineNumber, _ := decoder.InputPos()
+
+		switch element.Name.Local {
+		case "node":
+			handlers.Vertex(lineNumber, xmlAttribute(element, "id"))
+		case "edge":
+			handlers.Edge(lineNumber, []string{xmlAttribute(element, "source"), xmlAttribute(element, "target")}, "")
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"log"
+	"strconv"
+)
+
+// CoreNumbers finds the core number of each vertex, the largest k for which the vertex belongs to
+// a subgraph in which every vertex has at least k neighbours, with the bucket algorithm of
+// Batagelj and Zaversnik. Repeated edges count once.
+func (g *UndirectedGraph) CoreNumbers() []int {
+
+	neighbours := g.distinctNeighbours()
+	numberVertices := len(g.vertices)
+
+	// Sort the vertices by degree into bins, with the start of each bin
+	degree := make([]int, numberVertices)
+	maxDegree := 0
+	for v := range neighbours {
+		degree[v] = len(neighbours[v])
+		maxDegree = max(maxDegree, degree[v])
+	}
+
+	binStart := make([]int, maxDegree+1)
+	for _, d := range degree {
+		binStart[d]++
+	}
+	start := 0
+	for d, count := range binStart {
+		binStart[d] = start
+		start += count
+	}
+
+	sorted := make([]int, numberVertices)
+	position := make([]int, numberVertices)
+	for v, d := range degree {
+		position[v] = binStart[d]
+		sorted[position[v]] = v
+		binStart[d]++
+	}
+	for d := maxDegree; d > 0; d-- {
+		binStart[d] = binStart[d-1]
+	}
+	binStart[0] = 0
+
+	// Take the vertices in order of degree, moving each neighbour with a higher degree down a bin
+	for _, v := range sorted {
+		for _, u := range neighbours[v] {
+			if degree[u] > degree[v] {
+				firstInBin := sorted[binStart[degree[u]]]
+				if u != firstInBin {
+					sorted[position[u]], sorted[position[firstInBin]] = firstInBin, u
+					position[u], position[firstInBin] = position[firstInBin], position[u]
+				}
+				binStart[degree[u]]++
+				degree[u]--
+			}
+		}
+	}
+
+	return degree
+}
+
+// kCoreColumns finds the core number of each vertex and returns the output column giving it, with
+// the summary columns giving the maximum core number of each component and the number of members
+// in its innermost core, those with the maximum core number. Vertices without edges have a core
+// number of zero.
+func kCoreColumns(cc *ConnectedComponents, graph *UndirectedGraph) (VertexColumn, []SummaryColumn) {
+
+	log.Printf("Finding core numbers ...\n")
+
+	coreNumbers := graph.CoreNumbers()
+
+	column := VertexColumn{Header: "Core Number", Name: "core_number", Integers: map[string]int{}}
+	maxCore := SummaryColumn{Header: "Max Core", Values: map[int]string{}}
+	innermostCoreSize := SummaryColumn{Header: "Innermost Core Size", Values: map[int]string{}}
+	overallMaxCore := 0
+
+	for component, members := range cc.connectedComponentToVertices {
+		componentMaxCore := 0
+		numberInnermost := 0
+
+		for _, member := range members {
+			core := 0
+			if v, present := graph.vertexIndex[member]; present {
+				core = coreNumbers[v]
+			}
+			column.Integers[member] = core
+
+			if core > componentMaxCore {
+				componentMaxCore = core
+				numberInnermost = 0
+			}
+			if core == componentMaxCore {
+				numberInnermost++
+			}
+		}
+
+		maxCore.Values[component] = strconv.Itoa(componentMaxCore)
+		innermostCoreSize.Values[component] = strconv.Itoa(numberInnermost)
+		overallMaxCore = max(overallMaxCore, componentMaxCore)
+	}
+
+	log.Printf("Found a maximum core number of %v\n", overallMaxCore)
+
+	return column, []SummaryColumn{maxCore, innermostCoreSize}
+}
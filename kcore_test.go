@@ -0,0 +1,41 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCoreNumbers(t *testing.T) {
+
+	// A group of four with a triangle hanging off it, a tail and a repeated edge, which counts once
+	graph := NewUndirectedGraph()
+	for _, edge := range [][]string{
+		{"a", "b"}, {"a", "c"}, {"a", "d"}, {"b", "c"}, {"b", "d"}, {"c", "d"},
+		{"d", "e"}, {"e", "f"}, {"f", "d"}, {"f", "g"}, {"g", "h"}, {"h", "g"},
+	} {
+		graph.AddEdge(EntityPair{EntityID1: edge[0], EntityID2: edge[1]}, 0)
+	}
+	graph.AddVertex("i")
+
+	expected := []int{3, 3, 3, 3, 2, 2, 1, 1, 0}
+
+	if actual := graph.CoreNumbers(); !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected %v, got %v\n", expected, actual)
+	}
+}
+
+func TestCalculateCoreNumbers(t *testing.T) {
+
+	params := NewParameters("./test/test-9/edge_list.csv", "./test/test-9/actual_kcore.csv", ",")
+	params.KCore = true
+	params.SummaryFilepath = "./test/test-9/actual_kcore_summary.csv"
+	calculateConnectedComponentsWithParameters(params)
+
+	if !FilesHaveSameContent("./test/test-9/actual_kcore.csv", "./test/test-9/expected_kcore.csv") {
+		t.Fatal("Actual results differ from expected results")
+	}
+
+	if !FilesHaveSameContent("./test/test-9/actual_kcore_summary.csv", "./test/test-9/expected_kcore_summary.csv") {
+		t.Fatal("Actual component summary differs from expected component summary")
+	}
+}
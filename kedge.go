@@ -0,0 +1,259 @@
+package main
+
+import (
+	"container/heap"
+	"log"
+)
+
+// cutCandidate is a vertex in the priority queue of a minimum cut phase with its weight of edges
+// to the vertices already added
+type cutCandidate struct {
+	vertex int
+	weight int
+}
+
+// cutQueue is a max-heap of cut candidates, which may hold stale entries for a vertex whose
+// weight has since increased
+type cutQueue []cutCandidate
+
+func (q cutQueue) Len() int            { return len(q) }
+func (q cutQueue) Less(i, j int) bool  { return q[i].weight > q[j].weight }
+func (q cutQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *cutQueue) Push(x interface{}) { *q = append(*q, x.(cutCandidate)) }
+func (q *cutQueue) Pop() interface{} {
+	old := *q
+	candidate := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return candidate
+}
+
+// findCutBelow looks for a cut of fewer than k edges through a connected set of vertices with the
+// Stoer-Wagner algorithm, stopping at the first phase whose cut is small enough. It returns the
+// vertices on one side of the cut, or nil if every cut has at least k edges.
+func findCutBelow(neighbours [][]int, members []int, k int) []int {
+
+	// Number the members locally and count the edges between them
+	local := make(map[int]int, len(members))
+	for i, v := range members {
+		local[v] = i
+	}
+
+	weights := make([]map[int]int, len(members))
+	for i, v := range members {
+		weights[i] = map[int]int{}
+		for _, w := range neighbours[v] {
+			if j, present := local[w]; present {
+				weights[i][j] = 1
+			}
+		}
+	}
+
+	// Each local vertex stands for the members merged into it
+	merged := make([][]int, len(members))
+	for i, v := range members {
+		merged[i] = []int{v}
+	}
+
+	active := make([]int, len(members))
+	for i := range active {
+		active[i] = i
+	}
+
+	key := make([]int, len(members))
+	added := make([]bool, len(members))
+
+	for len(active) > 1 {
+
+		// Add the vertices in order of their weight to those already added
+		for _, v := range active {
+			key[v] = 0
+			added[v] = false
+		}
+
+		queue := &cutQueue{{vertex: active[0], weight: 0}}
+		previous, last := -1, -1
+
+		for queue.Len() > 0 {
+			candidate := heap.Pop(queue).(cutCandidate)
+			v := candidate.vertex
+			if added[v] || candidate.weight != key[v] {
+				continue
+			}
+
+			added[v] = true
+			previous, last = last, v
+
+			for w, weight := range weights[v] {
+				if !added[w] {
+					key[w] += weight
+					heap.Push(queue, cutCandidate{vertex: w, weight: key[w]})
+				}
+			}
+		}
+
+		// The cut of the phase separates the last vertex added from the rest
+		if key[last] < k {
+			return merged[last]
+		}
+
+		// Merge the last vertex into the one added before it
+		for w, weight := range weights[last] {
+			delete(weights[w], last)
+			if w != previous {
+				weights[previous][w] += weight
+				weights[w][previous] += weight
+			}
+		}
+		weights[last] = nil
+		merged[previous] = append(merged[previous], merged[last]...)
+		merged[last] = nil
+
+		for i, v := range active {
+			if v == last {
+				active = append(active[:i], active[i+1:]...)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// KEdgeConnectedSubgraphs splits the vertices into the largest subgraphs in which at least k edges
+// must be removed to disconnect any two vertices, with each remaining vertex on its own. Vertices
+// with fewer than k neighbours are set aside first, and each remaining set of vertices is split
+// along cuts of fewer than k edges until none are left. Repeated edges count once.
+func (g *UndirectedGraph) KEdgeConnectedSubgraphs(k int) [][]int {
+
+	// Precondition
+	if k < 1 {
+		log.Fatal("The edge connectivity of the subcomponents must be a positive integer")
+	}
+
+	neighbours := g.distinctNeighbours()
+
+	// inSet marks the vertices of the set being split with the number of that set
+	inSet := make([]int, len(g.vertices))
+	setNumber := 0
+	degree := make([]int, len(g.vertices))
+
+	subgraphs := [][]int{}
+	allVertices := make([]int, len(g.vertices))
+	for v := range allVertices {
+		allVertices[v] = v
+	}
+	sets := [][]int{allVertices}
+
+	for len(sets) > 0 {
+		set := sets[len(sets)-1]
+		sets = sets[:len(sets)-1]
+
+		if len(set) == 1 {
+			subgraphs = append(subgraphs, set)
+			continue
+		}
+
+		setNumber++
+		for _, v := range set {
+			inSet[v] = setNumber
+		}
+
+		// Set aside the vertices with fewer than k neighbours in the set, which can't belong to a
+		// k-edge-connected subgraph, until every vertex left has at least k
+		removed := []int{}
+		for _, v := range set {
+			degree[v] = 0
+			for _, w := range neighbours[v] {
+				if inSet[w] == setNumber {
+					degree[v]++
+				}
+			}
+			if degree[v] < k {
+				removed = append(removed, v)
+				inSet[v] = 0
+			}
+		}
+
+		for i := 0; i < len(removed); i++ {
+			subgraphs = append(subgraphs, []int{removed[i]})
+			for _, w := range neighbours[removed[i]] {
+				if inSet[w] == setNumber {
+					degree[w]--
+					if degree[w] < k {
+						removed = append(removed, w)
+						inSet[w] = 0
+					}
+				}
+			}
+		}
+
+		// Split the vertices left into connected parts and cut each part further if it can be cut
+		// with fewer than k edges
+		for _, start := range set {
+			if inSet[start] != setNumber {
+				continue
+			}
+
+			part := []int{start}
+			inSet[start] = -setNumber
+			for i := 0; i < len(part); i++ {
+				for _, w := range neighbours[part[i]] {
+					if inSet[w] == setNumber {
+						inSet[w] = -setNumber
+						part = append(part, w)
+					}
+				}
+			}
+
+			side := findCutBelow(neighbours, part, k)
+			if side == nil {
+				subgraphs = append(subgraphs, part)
+				continue
+			}
+
+			onSide := map[int]struct{}{}
+			for _, v := range side {
+				onSide[v] = struct{}{}
+			}
+			rest := []int{}
+			for _, v := range part {
+				if _, present := onSide[v]; !present {
+					rest = append(rest, v)
+				}
+			}
+			sets = append(sets, side, rest)
+		}
+	}
+
+	return subgraphs
+}
+
+// kEdgeColumn refines the connected components into k-edge-connected subcomponents and returns the
+// output column giving the hierarchical ID of the subcomponent of each vertex
+func kEdgeColumn(cc *ConnectedComponents, graph *UndirectedGraph, params Parameters) VertexColumn {
+
+	log.Printf("Finding %v-edge-connected subcomponents ...\n", params.KEdge)
+
+	subgraphs := graph.KEdgeConnectedSubgraphs(params.KEdge)
+
+	subgraphOf := make([]int, len(graph.vertices))
+	for i, vertices := range subgraphs {
+		for _, v := range vertices {
+			subgraphOf[v] = i
+		}
+	}
+
+	// Vertices without edges, which aren't in the graph, are subcomponents on their own
+	column, numberSubcomponents := hierarchicalColumn(cc, "Subcomponent ID", "subcomponent_id",
+		func(entityID string) (int, bool) {
+			v, present := graph.vertexIndex[entityID]
+			if !present {
+				return 0, false
+			}
+			return subgraphOf[v], true
+		})
+
+	log.Printf("Found %v %v-edge-connected subcomponents\n", numberSubcomponents, params.KEdge)
+
+	return column
+}
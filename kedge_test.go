@@ -0,0 +1,66 @@
+package main
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// sortedSubgraphs returns the entity IDs of each subgraph in sorted order, with the subgraphs
+// sorted by their first entity ID
+func sortedSubgraphs(graph *UndirectedGraph, subgraphs [][]int) [][]string {
+
+	sorted := [][]string{}
+	for _, vertices := range subgraphs {
+		members := []string{}
+		for _, v := range vertices {
+			members = append(members, graph.vertices[v])
+		}
+		sort.Strings(members)
+		sorted = append(sorted, members)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i][0] < sorted[j][0]
+	})
+
+	return sorted
+}
+
+func TestKEdgeConnectedSubgraphs(t *testing.T) {
+
+	// Two groups of four joined by two edges, with a vertex hanging off the second group, a pair
+	// joined by a repeated edge and a self-loop
+	graph := NewUndirectedGraph()
+	for _, edge := range [][]string{
+		{"a", "b"}, {"a", "c"}, {"a", "d"}, {"b", "c"}, {"b", "d"}, {"c", "d"},
+		{"e", "f"}, {"e", "g"}, {"e", "h"}, {"f", "g"}, {"f", "h"}, {"g", "h"},
+		{"a", "e"}, {"b", "f"}, {"h", "i"}, {"j", "k"}, {"k", "j"}, {"l", "l"},
+	} {
+		graph.AddEdge(EntityPair{EntityID1: edge[0], EntityID2: edge[1]}, 0)
+	}
+
+	expected := map[int][][]string{
+		1: {{"a", "b", "c", "d", "e", "f", "g", "h", "i"}, {"j", "k"}, {"l"}},
+		2: {{"a", "b", "c", "d", "e", "f", "g", "h"}, {"i"}, {"j"}, {"k"}, {"l"}},
+		3: {{"a", "b", "c", "d"}, {"e", "f", "g", "h"}, {"i"}, {"j"}, {"k"}, {"l"}},
+		4: {{"a"}, {"b"}, {"c"}, {"d"}, {"e"}, {"f"}, {"g"}, {"h"}, {"i"}, {"j"}, {"k"}, {"l"}},
+	}
+
+	for k, expectedSubgraphs := range expected {
+		if actual := sortedSubgraphs(graph, graph.KEdgeConnectedSubgraphs(k)); !reflect.DeepEqual(expectedSubgraphs, actual) {
+			t.Fatalf("Expected %v for k = %v, got %v\n", expectedSubgraphs, k, actual)
+		}
+	}
+}
+
+func TestCalculateKEdgeConnectedSubcomponents(t *testing.T) {
+
+	params := NewParameters("./test/test-9/edge_list.csv", "./test/test-9/actual_kedge_3.csv", ",")
+	params.KEdge = 3
+	calculateConnectedComponentsWithParameters(params)
+
+	if !FilesHaveSameContent("./test/test-9/actual_kedge_3.csv", "./test/test-9/expected_kedge_3.csv") {
+		t.Fatal("Actual results differ from expected results")
+	}
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"log"
+	"sort"
+)
+
+// louvainEdge is an edge to a neighbour in a louvainGraph with its weight
+type louvainEdge struct {
+	vertex int
+	weight float64
+}
+
+// louvainGraph is a weighted undirected graph in which each vertex may stand for a community of
+// the level below, with the weight of the edges within that community held as a self-loop
+type louvainGraph struct {
+	neighbours [][]louvainEdge
+	selfLoops  []float64
+}
+
+// newLouvainGraph sets up the graph of the members of a connected component, where the weights of
+// repeated edges between two members add up to the weight of the edge between them. Edges with a
+// weight of zero or less are left out, as modularity is only defined for positive weights.
+func newLouvainGraph(g *UndirectedGraph, members []int) louvainGraph {
+
+	local := make(map[int]int, len(members))
+	for i, v := range members {
+		local[v] = i
+	}
+
+	weights := make([]map[int]float64, len(members))
+	for i, v := range members {
+		weights[i] = map[int]float64{}
+		for _, edge := range g.incident[v] {
+			if weight := g.edges[edge].weight; weight > 0 {
+				weights[i][local[g.otherVertex(edge, v)]] += weight
+			}
+		}
+	}
+
+	return louvainGraphFromWeights(weights, make([]float64, len(members)))
+}
+
+// louvainGraphFromWeights builds a louvainGraph from the weights between each pair of vertices,
+// with the neighbours of each vertex in order so that the communities found don't depend on the
+// order of map iteration
+func louvainGraphFromWeights(weights []map[int]float64, selfLoops []float64) louvainGraph {
+
+	graph := louvainGraph{neighbours: make([][]louvainEdge, len(weights)), selfLoops: selfLoops}
+
+	for v, vertexWeights := range weights {
+		neighbours := make([]int, 0, len(vertexWeights))
+		for w := range vertexWeights {
+			neighbours = append(neighbours, w)
+		}
+		sort.Ints(neighbours)
+
+		for _, w := range neighbours {
+			graph.neighbours[v] = append(graph.neighbours[v], louvainEdge{vertex: w, weight: vertexWeights[w]})
+		}
+	}
+
+	return graph
+}
+
+// moveVertices moves each vertex in turn to the neighbouring community that most increases the
+// modularity, until no move increases it. It returns the community of each vertex, numbered from
+// zero in the order of their first vertex, the number of communities and whether any vertex moved.
+func (g louvainGraph) moveVertices() ([]int, int, bool) {
+
+	numberVertices := len(g.neighbours)
+
+	// The degree of each vertex and the total degree of each community
+	degree := make([]float64, numberVertices)
+	total := make([]float64, numberVertices)
+	community := make([]int, numberVertices)
+	totalDegree := 0.0
+
+	for v, edges := range g.neighbours {
+		degree[v] = 2 * g.selfLoops[v]
+		for _, edge := range edges {
+			degree[v] += edge.weight
+		}
+		total[v] = degree[v]
+		community[v] = v
+		totalDegree += degree[v]
+	}
+
+	moved := false
+
+	if totalDegree > 0 {
+		weightTo := make([]float64, numberVertices)
+		neighbourCommunities := []int{}
+
+		for improved := true; improved; {
+			improved = false
+
+			for v, edges := range g.neighbours {
+
+				// Take the vertex out of its community and find the weight of its edges to each
+				// neighbouring community
+				current := community[v]
+				total[current] -= degree[v]
+
+				neighbourCommunities = neighbourCommunities[:0]
+				for _, edge := range edges {
+					c := community[edge.vertex]
+					if weightTo[c] == 0 {
+						neighbourCommunities = append(neighbourCommunities, c)
+					}
+					weightTo[c] += edge.weight
+				}
+
+				// Put it in the community with the largest gain in modularity, staying put on a tie
+				best := current
+				bestGain := weightTo[current] - total[current]*degree[v]/totalDegree
+				for _, c := range neighbourCommunities {
+					if gain := weightTo[c] - total[c]*degree[v]/totalDegree; gain > bestGain+1e-12 {
+						best, bestGain = c, gain
+					}
+				}
+
+				total[best] += degree[v]
+				community[v] = best
+				if best != current {
+					improved = true
+					moved = true
+				}
+
+				for _, c := range neighbourCommunities {
+					weightTo[c] = 0
+				}
+				weightTo[current] = 0
+			}
+		}
+	}
+
+	// Number the communities in the order of their first vertex
+	number := map[int]int{}
+	for v, c := range community {
+		if _, present := number[c]; !present {
+			number[c] = len(number)
+		}
+		community[v] = number[c]
+	}
+
+	return community, len(number), moved
+}
+
+// aggregate builds the graph of the next level, with a vertex for each community
+func (g louvainGraph) aggregate(community []int, numberCommunities int) louvainGraph {
+
+	weights := make([]map[int]float64, numberCommunities)
+	for c := range weights {
+		weights[c] = map[int]float64{}
+	}
+	selfLoops := make([]float64, numberCommunities)
+
+	for v, edges := range g.neighbours {
+		selfLoops[community[v]] += g.selfLoops[v]
+		for _, edge := range edges {
+			if community[v] == community[edge.vertex] {
+				// Each edge within a community is seen from both of its ends
+				selfLoops[community[v]] += edge.weight / 2
+			} else {
+				weights[community[v]][community[edge.vertex]] += edge.weight
+			}
+		}
+	}
+
+	return louvainGraphFromWeights(weights, selfLoops)
+}
+
+// louvainCommunities finds communities of the vertices with the Louvain method, moving vertices
+// between communities and then merging each community into a single vertex until the modularity
+// stops increasing. It returns the community of each vertex, numbered in the order of their first
+// vertex.
+func louvainCommunities(g louvainGraph) []int {
+
+	membership := make([]int, len(g.neighbours))
+	for v := range membership {
+		membership[v] = v
+	}
+
+	for {
+		community, numberCommunities, moved := g.moveVertices()
+		if !moved {
+			break
+		}
+
+		for v := range membership {
+			membership[v] = community[membership[v]]
+		}
+
+		g = g.aggregate(community, numberCommunities)
+	}
+
+	return membership
+}
+
+// communityColumn finds communities with the Louvain method within each connected component with
+// at least the minimum number of members, and returns the output column giving the hierarchical ID
+// of the community of each vertex. Each smaller component is a single community. Edges are weighted
+// by the weights given by the input, or 1 where none is given, so repeated edges add to the weight
+// of the edge between two members.
+func communityColumn(cc *ConnectedComponents, graph *UndirectedGraph, params Parameters) VertexColumn {
+
+	// Precondition
+	if params.CommunityMinSize < 1 {
+		log.Fatal("The minimum size of a component to find communities in must be a positive integer")
+	}
+
+	log.Printf("Finding communities in components with at least %v members ...\n", params.CommunityMinSize)
+
+	isLarge := func(entityID string) bool {
+		return len(cc.connectedComponentToVertices[cc.vertexToConnectedComponent[entityID]]) >= params.CommunityMinSize
+	}
+
+	// Find the communities of each large component separately
+	communityOf := make([]int, len(graph.vertices))
+	numberLargeComponents := 0
+
+	for _, component := range *sortedListComponents(&cc.connectedComponentToVertices) {
+		members := cc.connectedComponentToVertices[component]
+		if len(members) < params.CommunityMinSize {
+			continue
+		}
+
+		vertices := []int{}
+		for _, member := range members {
+			if v, present := graph.vertexIndex[member]; present {
+				vertices = append(vertices, v)
+			}
+		}
+
+		for i, community := range louvainCommunities(newLouvainGraph(graph, vertices)) {
+			communityOf[vertices[i]] = community
+		}
+		numberLargeComponents++
+	}
+
+	column, numberCommunities := hierarchicalColumn(cc, "Community ID", "community_id",
+		func(entityID string) (int, bool) {
+			if !isLarge(entityID) {
+				return 0, true
+			}
+			v, present := graph.vertexIndex[entityID]
+			if !present {
+				return 0, false
+			}
+			return communityOf[v], true
+		})
+
+	log.Printf("Found %v communities, searching %v components\n", numberCommunities, numberLargeComponents)
+
+	return column
+}
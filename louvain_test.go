@@ -0,0 +1,67 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLouvainCommunities(t *testing.T) {
+
+	// Two triangles joined by a single edge, and the same with the joining edge repeated or
+	// weighted so that it outweighs the triangles. A joining edge without weight is left out.
+	for _, test := range []struct {
+		repeats  int
+		weight   float64
+		expected []int
+	}{
+		{repeats: 1, weight: 1, expected: []int{0, 0, 0, 1, 1, 1}},
+		{repeats: 20, weight: 1, expected: []int{0, 0, 1, 1, 2, 2}},
+		{repeats: 1, weight: 20, expected: []int{0, 0, 1, 1, 2, 2}},
+		{repeats: 10, weight: 2, expected: []int{0, 0, 1, 1, 2, 2}},
+		{repeats: 1, weight: 0, expected: []int{0, 0, 0, 1, 1, 1}},
+	} {
+		graph := NewUndirectedGraph()
+		for _, edge := range [][]string{{"a", "b"}, {"b", "c"}, {"c", "a"}, {"d", "e"}, {"e", "f"}, {"f", "d"}} {
+			graph.AddEdge(EntityPair{EntityID1: edge[0], EntityID2: edge[1]}, 0)
+		}
+		for i := 0; i < test.repeats; i++ {
+			graph.AddWeightedEdge(EntityPair{EntityID1: "c", EntityID2: "d"}, 0, test.weight)
+		}
+
+		vertices := []int{0, 1, 2, 3, 4, 5}
+		if actual := louvainCommunities(newLouvainGraph(graph, vertices)); !reflect.DeepEqual(test.expected, actual) {
+			t.Fatalf("Expected %v with %v repeats of weight %v, got %v\n", test.expected, test.repeats, test.weight, actual)
+		}
+	}
+}
+
+func TestCalculateCommunities(t *testing.T) {
+
+	// Communities are only found in the component with at least 5 members
+	params := NewParameters("./test/test-9/edge_list.csv", "./test/test-9/actual_communities.csv", ",")
+	params.Communities = true
+	params.CommunityMinSize = 5
+	calculateConnectedComponentsWithParameters(params)
+
+	if !FilesHaveSameContent("./test/test-9/actual_communities.csv", "./test/test-9/expected_communities.csv") {
+		t.Fatal("Actual results differ from expected results")
+	}
+}
+
+func TestCalculateCommunitiesWeighted(t *testing.T) {
+
+	// The weight of the edge joining two triangles, in the third field, outweighs the triangles,
+	// whether the edge list is parsed on one goroutine or several
+	for _, workers := range []int{1, 2} {
+		params := NewParameters("./test/test-12/edge_list.csv", "./test/test-12/actual_communities.csv", ",")
+		params.WeightColumn = "3"
+		params.Workers = workers
+		params.Communities = true
+		params.CommunityMinSize = 5
+		calculateConnectedComponentsWithParameters(params)
+
+		if !FilesHaveSameContent("./test/test-12/actual_communities.csv", "./test/test-12/expected_communities.csv") {
+			t.Fatalf("Actual results differ from expected results with %v workers\n", workers)
+		}
+	}
+}
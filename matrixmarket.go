@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bufio"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// MatrixMarketEdgeSource reads a graph from the adjacency matrix in a Matrix Market coordinate file
+type MatrixMarketEdgeSource struct {
+	filepath string
+}
+
+// ReadEdges reads the Matrix Market file, passing each row and column number as a vertex and each
+// stored entry as an edge between its row and column, with the value of an integer or real entry as
+// the weight of the edge. The matrix must be square.
+func (s MatrixMarketEdgeSource) ReadEdges(handlers EdgeHandlers) {
+
+	// Open the file for reading and ensure it is closed
+	file, err := os.Open(s.filepath)
+	if err != nil {
+		log.Fatal("[!] Couldn't open Matrix Market file ", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+
+	// The header gives the object, format, field and symmetry
+	if !scanner.Scan() {
+		log.Fatal("[!] Matrix Market file is empty")
+	}
+
+	header := strings.Fields(strings.ToLower(scanner.Text()))
+	if len(header) < 3 || header[0] != "%%matrixmarket" {
+		log.Fatal("[!] Matrix Market file doesn't start with a MatrixMarket banner")
+	}
+
+	if header[1] != "matrix" || header[2] != "coordinate" {
+		log.Fatalf("[!] Only Matrix Market coordinate matrices are supported, found %v %v\n", header[1], header[2])
+	}
+
+	// Only integer and real entries have a value that can be an edge weight
+	weighted := len(header) >= 4 && (header[3] == "integer" || header[3] == "real" || header[3] == "double")
+
+	lineNumber := 1
+	numberVertices := -1
+
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		fields := strings.Fields(line)
+
+		// Skip blank lines and comments
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "%") {
+			continue
+		}
+
+		// The size line gives the number of rows, columns and entries
+		if numberVertices < 0 {
+			if len(fields) < 3 {
+				log.Fatalf("[!] Error parsing Matrix Market file: invalid size line at line %v\n", lineNumber)
+			}
+
+			rows, rowsErr := strconv.Atoi(fields[0])
+			columns, columnsErr := strconv.Atoi(fields[1])
+			if rowsErr != nil || columnsErr != nil || rows < 0 {
+				log.Fatalf("[!] Error parsing Matrix Market file: invalid size line at line %v\n", lineNumber)
+			}
+
+			if rows != columns {
+				log.Fatalf("[!] Matrix Market adjacency matrix must be square, found %v rows and %v columns\n", rows, columns)
+			}
+
+			numberVertices = rows
+			for i := 1; i <= numberVertices; i++ {
+				handlers.Vertex(lineNumber, strconv.Itoa(i))
+			}
+			continue
+		}
+
+		// Entries give the row and column numbers followed by any value
+		if len(fields) < 2 {
+			handlers.ParseError(lineNumber, line, "expected a row and column number")
+			continue
+		}
+
+		row, rowErr := strconv.Atoi(fields[0])
+		column, columnErr := strconv.Atoi(fields[1])
+		if rowErr != nil || columnErr != nil || row < 1 || row > numberVertices || column < 1 || column > numberVertices {
+			handlers.ParseError(lineNumber, line, "row or column number out of range")
+			continue
+		}
+
+		weight := ""
+		if weighted && len(fields) >= 3 {
+			weight = fields[2]
+		}
+
+		handlers.Edge(lineNumber, []string{strconv.Itoa(row), strconv.Itoa(column)}, weight)
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Fatal("[!] Error reading Matrix Market file: ", err)
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ComponentMembers is the JSON Lines representation of a connected component and its members
+type ComponentMembers struct {
+	ComponentID int      `json:"component_id"`
+	Size        int      `json:"size"`
+	Members     []string `json:"members"`
+}
+
+// membersHeader builds the header of the component members file
+func membersHeader(delimiter string) string {
+
+	// Precondition
+	if len(delimiter) == 0 {
+		log.Fatal("Cannot use a blank delimiter")
+	}
+
+	return "Component ID" + delimiter + "Size" + delimiter + "Members"
+}
+
+// buildMembersLine builds a line of the component members file from the sorted members
+func buildMembersLine(component int, members []string, delimiter string, memberDelimiter string) string {
+
+	// Preconditions
+	if component < 0 {
+		log.Fatal("Component IDs must be positive integers")
+	}
+
+	if len(memberDelimiter) == 0 {
+		log.Fatal("Cannot use a blank member delimiter")
+	}
+
+	return strconv.Itoa(component) + delimiter + strconv.Itoa(len(members)) + delimiter +
+		strings.Join(members, memberDelimiter)
+}
+
+// sortedListComponents returns a sorted list of the connected component IDs
+func sortedListComponents(componentToVertices *map[int][]string) *[]int {
+
+	// Get a slice of the keys
+	keys := make([]int, 0, len(*componentToVertices))
+	for k := range *componentToVertices {
+		keys = append(keys, k)
+	}
+
+	// Sort the slice
+	sort.Ints(keys)
+
+	return &keys
+}
+
+// sortedMembers returns a sorted copy of the members of a connected component
+func sortedMembers(members []string) []string {
+	sorted := append([]string{}, members...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+// writeMembersToFile writes one line per connected component with its size and sorted members
+func writeMembersToFile(
+	componentToVertices *map[int][]string,
+	filepath string,
+	delimiter string,
+	memberDelimiter string) {
+
+	// Open the output CSV file for writing
+	outputFile, err := os.Create(filepath)
+	if err != nil {
+		log.Fatalf("[!] Unable to open output file %v for writing: %v\n", filepath, err)
+	}
+	defer outputFile.Close()
+
+	writer := bufio.NewWriter(outputFile)
+	defer writer.Flush()
+
+	// Write the header
+	fmt.Fprintln(writer, membersHeader(delimiter))
+
+	// Write each connected component in order of its ID
+	numberComponentsWritten := 0
+	for _, component := range *sortedListComponents(componentToVertices) {
+
+		members := sortedMembers((*componentToVertices)[component])
+		fmt.Fprintln(writer, buildMembersLine(component, members, delimiter, memberDelimiter))
+
+		numberComponentsWritten++
+
+		if numberComponentsWritten%1000000 == 0 {
+			log.Printf("Number of components written to file: %v\n", numberComponentsWritten)
+		}
+	}
+}
+
+// writeMembersToJSONLinesFile writes one JSON object per connected component with its size and
+// sorted members
+func writeMembersToJSONLinesFile(componentToVertices *map[int][]string, filepath string) {
+
+	// Open the output JSON Lines file for writing
+	outputFile, err := os.Create(filepath)
+	if err != nil {
+		log.Fatalf("[!] Unable to open output file %v for writing: %v\n", filepath, err)
+	}
+	defer outputFile.Close()
+
+	writer := bufio.NewWriter(outputFile)
+	defer writer.Flush()
+
+	encoder := json.NewEncoder(writer)
+
+	// Write each connected component in order of its ID
+	numberComponentsWritten := 0
+	for _, component := range *sortedListComponents(componentToVertices) {
+
+		members := sortedMembers((*componentToVertices)[component])
+
+		err := encoder.Encode(ComponentMembers{
+			ComponentID: component,
+			Size:        len(members),
+			Members:     members,
+		})
+		if err != nil {
+			log.Fatalf("[!] Unable to write to output file %v: %v\n", filepath, err)
+		}
+
+		numberComponentsWritten++
+
+		if numberComponentsWritten%1000000 == 0 {
+			log.Printf("Number of components written to file: %v\n", numberComponentsWritten)
+		}
+	}
+}
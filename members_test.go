@@ -0,0 +1,79 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMembersHeader(t *testing.T) {
+	actual := membersHeader(",")
+	expected := "Component ID,Size,Members"
+
+	if expected != actual {
+		t.Fatalf("Expected %v, got %v\n", expected, actual)
+	}
+}
+
+func TestBuildMembersLine(t *testing.T) {
+	actual := buildMembersLine(4, []string{"e-1", "e-10", "e-2"}, ",", ";")
+	expected := "4,3,e-1;e-10;e-2"
+
+	if expected != actual {
+		t.Fatalf("Expected %v, got %v\n", expected, actual)
+	}
+}
+
+func TestSortedListComponents(t *testing.T) {
+	m := map[int][]string{
+		3: []string{"e-4"},
+		0: []string{"e-1", "e-2"},
+		1: []string{"e-3"},
+	}
+
+	actual := sortedListComponents(&m)
+	expected := []int{0, 1, 3}
+
+	if !reflect.DeepEqual(expected, *actual) {
+		t.Fatalf("Expected %v, got %v\n", expected, *actual)
+	}
+}
+
+func TestSortedMembersLeavesInputUnchanged(t *testing.T) {
+	members := []string{"e-2", "e-1"}
+
+	actual := sortedMembers(members)
+
+	if !reflect.DeepEqual([]string{"e-1", "e-2"}, actual) {
+		t.Fatalf("Expected %v, got %v\n", []string{"e-1", "e-2"}, actual)
+	}
+
+	if !reflect.DeepEqual([]string{"e-2", "e-1"}, members) {
+		t.Fatalf("Expected the input to be unchanged, got %v\n", members)
+	}
+}
+
+func TestCalculateConnectedComponentsMembers(t *testing.T) {
+
+	// Calculate the connected components and write the members of each component
+	params := NewParameters("./test/test-2/edge_list.csv", "./test/test-2/actual_members.csv", ",")
+	params.OutputFormat = "members"
+	calculateConnectedComponentsWithParameters(params)
+
+	// Read the actual and expected results
+	if !FilesHaveSameContent("./test/test-2/actual_members.csv", "./test/test-2/expected_members.csv") {
+		t.Fatal("Actual results differ from expected results")
+	}
+}
+
+func TestCalculateConnectedComponentsMembersJSONLines(t *testing.T) {
+
+	// Calculate the connected components and write the members of each component as JSON Lines
+	params := NewParameters("./test/test-2/edge_list.csv", "./test/test-2/actual_members.jsonl", ",")
+	params.OutputFormat = "members-jsonl"
+	calculateConnectedComponentsWithParameters(params)
+
+	// Read the actual and expected results
+	if !FilesHaveSameContent("./test/test-2/actual_members.jsonl", "./test/test-2/expected_members.jsonl") {
+		t.Fatal("Actual results differ from expected results")
+	}
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"log"
+	"strconv"
+)
+
+// ComponentMetrics holds the structural metrics of a connected component. The diameter, radius and
+// centre are exact for small components; for large components the diameter is a lower bound found
+// by a double sweep, the centre is the middle of the path it found and the radius is the
+// eccentricity of that centre, and the highest betweenness member isn't found.
+type ComponentMetrics struct {
+	Diameter                 int
+	Radius                   int
+	Centre                   string
+	HighestDegreeMember      string
+	HighestBetweennessMember string
+	Exact                    bool
+}
+
+// metricsSearch holds the state of the breadth-first searches over a graph, sized for the whole
+// graph and reset after each search for the vertices it reached
+type metricsSearch struct {
+	graph       *UndirectedGraph
+	neighbours  [][]int
+	distance    []int
+	parent      []int
+	paths       []float64
+	dependency  []float64
+	betweenness []float64
+	order       []int
+}
+
+// newMetricsSearch sets up the searches over a graph, following the distinct neighbours of each vertex
+func newMetricsSearch(graph *UndirectedGraph) *metricsSearch {
+
+	neighbours := graph.distinctNeighbours()
+	s := &metricsSearch{
+		graph:       graph,
+		neighbours:  neighbours,
+		distance:    make([]int, len(neighbours)),
+		parent:      make([]int, len(neighbours)),
+		paths:       make([]float64, len(neighbours)),
+		dependency:  make([]float64, len(neighbours)),
+		betweenness: make([]float64, len(neighbours)),
+	}
+
+	for v := range s.distance {
+		s.distance[v] = -1
+	}
+
+	return s
+}
+
+// search finds the distance from the source to each vertex it can reach, in the order they are
+// reached, and the number of shortest paths to each
+func (s *metricsSearch) search(source int) {
+
+	s.order = append(s.order[:0], source)
+	s.distance[source] = 0
+	s.parent[source] = -1
+	s.paths[source] = 1
+
+	for i := 0; i < len(s.order); i++ {
+		v := s.order[i]
+		for _, w := range s.neighbours[v] {
+			if s.distance[w] < 0 {
+				s.distance[w] = s.distance[v] + 1
+				s.parent[w] = v
+				s.order = append(s.order, w)
+			}
+			if s.distance[w] == s.distance[v]+1 {
+				s.paths[w] += s.paths[v]
+			}
+		}
+	}
+}
+
+// reset clears the distances and path counts of the vertices reached by the last search
+func (s *metricsSearch) reset() {
+	for _, v := range s.order {
+		s.distance[v] = -1
+		s.paths[v] = 0
+		s.dependency[v] = 0
+	}
+}
+
+// farthest returns the last vertex reached by the last search and its distance from the source
+func (s *metricsSearch) farthest() (int, int) {
+	v := s.order[len(s.order)-1]
+	return v, s.distance[v]
+}
+
+// accumulateBetweenness adds the dependencies of the source of the last search to the
+// betweenness of each vertex, with Brandes' algorithm
+func (s *metricsSearch) accumulateBetweenness() {
+
+	for i := len(s.order) - 1; i > 0; i-- {
+		w := s.order[i]
+		for _, v := range s.neighbours[w] {
+			if s.distance[v] == s.distance[w]-1 {
+				s.dependency[v] += s.paths[v] / s.paths[w] * (1 + s.dependency[w])
+			}
+		}
+		s.betweenness[w] += s.dependency[w]
+	}
+}
+
+// betterMember returns whether a member with a score should replace the best member so far, where
+// lower scores are better if lower is true, with ties broken by entity ID
+func betterMember(score float64, entityID string, bestScore float64, bestEntityID string, lower bool) bool {
+	if score != bestScore {
+		return (score < bestScore) == lower
+	}
+	return entityID < bestEntityID
+}
+
+// metrics finds the metrics of a connected component from the entity IDs of its members, exactly
+// if it has at most the given number of members
+func (s *metricsSearch) metrics(vertices []string, exactSize int) ComponentMetrics {
+
+	// A member without edges is a component on its own
+	if len(vertices) == 1 {
+		return ComponentMetrics{Centre: vertices[0], HighestDegreeMember: vertices[0],
+			HighestBetweennessMember: vertices[0], Exact: true}
+	}
+
+	members := make([]int, len(vertices))
+	for i, entityID := range vertices {
+		members[i] = s.graph.vertexIndex[entityID]
+	}
+
+	m := ComponentMetrics{Exact: len(members) <= exactSize}
+
+	bestDegree := -1.0
+	for i, v := range members {
+		if degree := float64(len(s.neighbours[v])); bestDegree < 0 ||
+			betterMember(degree, vertices[i], bestDegree, m.HighestDegreeMember, false) {
+			bestDegree, m.HighestDegreeMember = degree, vertices[i]
+		}
+	}
+
+	if m.Exact {
+
+		// Search from every member, finding its eccentricity and its share of the betweenness
+		m.Radius = -1
+		for i, v := range members {
+			s.search(v)
+			_, eccentricity := s.farthest()
+			s.accumulateBetweenness()
+			s.reset()
+
+			m.Diameter = max(m.Diameter, eccentricity)
+			if m.Radius < 0 || betterMember(float64(eccentricity), vertices[i], float64(m.Radius), m.Centre, true) {
+				m.Radius, m.Centre = eccentricity, vertices[i]
+			}
+		}
+
+		bestBetweenness := -1.0
+		for i, v := range members {
+			if bestBetweenness < 0 ||
+				betterMember(s.betweenness[v], vertices[i], bestBetweenness, m.HighestBetweennessMember, false) {
+				bestBetweenness, m.HighestBetweennessMember = s.betweenness[v], vertices[i]
+			}
+			s.betweenness[v] = 0
+		}
+
+		return m
+	}
+
+	// Sweep from the first member to the farthest member from it, then from there to the farthest
+	// member from that
+	s.search(members[0])
+	start, _ := s.farthest()
+	s.reset()
+
+	s.search(start)
+	end, diameter := s.farthest()
+	centre := end
+	for step := 0; step < diameter/2; step++ {
+		centre = s.parent[centre]
+	}
+	s.reset()
+
+	s.search(centre)
+	_, radius := s.farthest()
+	s.reset()
+
+	m.Diameter = diameter
+	m.Radius = radius
+	m.Centre = s.graph.vertices[centre]
+
+	return m
+}
+
+// metricsColumns finds the metrics of each connected component within the size range and returns
+// the summary columns giving them. Components with at most the exact size number of members have
+// their metrics found exactly; the others are approximated. Repeated edges count once.
+func metricsColumns(cc *ConnectedComponents, graph *UndirectedGraph, params Parameters) []SummaryColumn {
+
+	// Precondition
+	if params.MetricsExactSize < 0 {
+		log.Fatal("The largest size of a component with exact metrics must not be negative")
+	}
+
+	log.Printf("Finding component metrics, exactly for components with up to %v members ...\n", params.MetricsExactSize)
+
+	search := newMetricsSearch(graph)
+
+	columns := []SummaryColumn{
+		{Header: "Diameter", Values: map[int]string{}},
+		{Header: "Radius", Values: map[int]string{}},
+		{Header: "Centre", Values: map[int]string{}},
+		{Header: "Highest Degree Member", Values: map[int]string{}},
+		{Header: "Highest Betweenness Member", Values: map[int]string{}},
+		{Header: "Exact Metrics", Values: map[int]string{}},
+	}
+
+	numberExact := 0
+	numberApproximate := 0
+
+	filteredComponentToVertices, _, _ := filterComponents(&cc.connectedComponentToVertices, params.SizeFilter)
+	for component, members := range *filteredComponentToVertices {
+
+		m := search.metrics(members, params.MetricsExactSize)
+
+		columns[0].Values[component] = strconv.Itoa(m.Diameter)
+		columns[1].Values[component] = strconv.Itoa(m.Radius)
+		columns[2].Values[component] = m.Centre
+		columns[3].Values[component] = m.HighestDegreeMember
+		columns[4].Values[component] = m.HighestBetweennessMember
+		columns[5].Values[component] = strconv.FormatBool(m.Exact)
+
+		if m.Exact {
+			numberExact++
+		} else {
+			numberApproximate++
+		}
+	}
+
+	log.Printf("Found exact metrics of %v components and approximate metrics of %v components\n",
+		numberExact, numberApproximate)
+
+	return columns
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestComponentMetrics(t *testing.T) {
+
+	// A path of five vertices with a triangle at one end, where the repeated edge counts once
+	graph := NewUndirectedGraph()
+	for _, edge := range [][]string{{"a", "b"}, {"b", "c"}, {"c", "d"}, {"d", "e"}, {"e", "c"}, {"e", "c"}} {
+		graph.AddEdge(EntityPair{EntityID1: edge[0], EntityID2: edge[1]}, 0)
+	}
+	vertices := []string{"a", "b", "c", "d", "e"}
+
+	search := newMetricsSearch(graph)
+
+	expected := ComponentMetrics{Diameter: 3, Radius: 2, Centre: "b", HighestDegreeMember: "c",
+		HighestBetweennessMember: "c", Exact: true}
+	if actual := search.metrics(vertices, 5); !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected %v, got %v\n", expected, actual)
+	}
+
+	// The double sweep runs from the far end of the triangle back to a, with b in the middle
+	expected = ComponentMetrics{Diameter: 3, Radius: 2, Centre: "b", HighestDegreeMember: "c", Exact: false}
+	if actual := search.metrics(vertices, 4); !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected %v, got %v\n", expected, actual)
+	}
+}
+
+func TestCalculateComponentMetrics(t *testing.T) {
+
+	params := NewParameters("./test/test-9/edge_list.csv", "./test/test-9/actual.csv", ",")
+	params.Metrics = true
+	params.SummaryFilepath = "./test/test-9/actual_metrics_summary.csv"
+	calculateConnectedComponentsWithParameters(params)
+
+	if !FilesHaveSameContent("./test/test-9/actual_metrics_summary.csv", "./test/test-9/expected_metrics_summary.csv") {
+		t.Fatal("Actual component summary differs from expected component summary")
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normaliser applies a configurable set of rules to entity IDs before they are linked
+type Normaliser struct {
+	unicodeForm       *norm.Form
+	trim              bool
+	caseFold          bool
+	caser             cases.Caser
+	stripLeadingZeros bool
+	rewritePattern    *regexp.Regexp
+	rewriteTemplate   string
+	recordRawIDs      bool
+	rawIDs            map[string]map[string]struct{}
+}
+
+// NewNormaliser sets up a Normaliser from a comma-separated list of rules (trim, casefold, nfc,
+// nfkc, zeros) and an optional regular expression rewrite applied after the other rules
+func NewNormaliser(rules string, rewritePattern string, rewriteTemplate string, recordRawIDs bool) *Normaliser {
+
+	n := Normaliser{
+		recordRawIDs: recordRawIDs,
+		rawIDs:       map[string]map[string]struct{}{},
+	}
+
+	for _, rule := range strings.Split(rules, ",") {
+		switch strings.ToLower(strings.TrimSpace(rule)) {
+		case "":
+			continue
+		case "trim":
+			n.trim = true
+		case "casefold":
+			n.caseFold = true
+			n.caser = cases.Fold()
+		case "nfc":
+			setUnicodeForm(&n, norm.NFC)
+		case "nfkc":
+			setUnicodeForm(&n, norm.NFKC)
+		case "zeros":
+			n.stripLeadingZeros = true
+		default:
+			log.Fatalf("[!] Unknown normalisation rule: %v\n", rule)
+		}
+	}
+
+	if len(rewritePattern) > 0 {
+		pattern, err := regexp.Compile(rewritePattern)
+		if err != nil {
+			log.Fatalf("[!] Invalid rewrite pattern %v: %v\n", rewritePattern, err)
+		}
+		n.rewritePattern = pattern
+		n.rewriteTemplate = rewriteTemplate
+	}
+
+	return &n
+}
+
+// setUnicodeForm sets the Unicode normalisation form, only allowing one form to be chosen
+func setUnicodeForm(n *Normaliser, form norm.Form) {
+	if n.unicodeForm != nil && *n.unicodeForm != form {
+		log.Fatal("[!] Only one of nfc and nfkc can be used")
+	}
+	n.unicodeForm = &form
+}
+
+// Normalise returns the normalised key for an entity ID; a nil Normaliser leaves the ID unchanged
+func (n *Normaliser) Normalise(entityID string) string {
+
+	if n == nil {
+		return entityID
+	}
+
+	key := entityID
+
+	if n.unicodeForm != nil {
+		key = n.unicodeForm.String(key)
+	}
+
+	if n.trim {
+		key = strings.TrimSpace(key)
+	}
+
+	if n.caseFold {
+		key = n.caser.String(key)
+
+		// Case folding can undo the Unicode normal form, so the form is applied again
+		if n.unicodeForm != nil {
+			key = n.unicodeForm.String(key)
+		}
+	}
+
+	if n.stripLeadingZeros {
+		key = trimLeadingZeros(key)
+	}
+
+	if n.rewritePattern != nil {
+		key = n.rewritePattern.ReplaceAllString(key, n.rewriteTemplate)
+	}
+
+	if n.recordRawIDs {
+		n.recordRawID(key, entityID)
+	}
+
+	return key
+}
+
+// trimLeadingZeros removes leading zeros from an ID, leaving a single zero if the ID is all zeros
+func trimLeadingZeros(entityID string) string {
+	trimmed := strings.TrimLeft(entityID, "0")
+	if len(trimmed) == 0 && len(entityID) > 0 {
+		return "0"
+	}
+	return trimmed
+}
+
+// recordRawID adds a raw ID to the set of raw IDs of its normalised key
+func (n *Normaliser) recordRawID(key string, rawID string) {
+	rawIDs, present := n.rawIDs[key]
+	if !present {
+		rawIDs = map[string]struct{}{}
+		n.rawIDs[key] = rawIDs
+	}
+	rawIDs[rawID] = struct{}{}
+}
+
+// RawIDs returns the sorted raw IDs seen for a normalised key
+func (n *Normaliser) RawIDs(key string) []string {
+
+	if n == nil || !n.recordRawIDs {
+		return []string{key}
+	}
+
+	rawIDs := make([]string, 0, len(n.rawIDs[key]))
+	for rawID := range n.rawIDs[key] {
+		rawIDs = append(rawIDs, rawID)
+	}
+	sort.Strings(rawIDs)
+
+	return rawIDs
+}
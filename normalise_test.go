@@ -0,0 +1,131 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormaliseNilNormaliser(t *testing.T) {
+	var n *Normaliser
+
+	actual := n.Normalise(" E-1 ")
+	expected := " E-1 "
+
+	if expected != actual {
+		t.Fatalf("Expected %v, got %v\n", expected, actual)
+	}
+}
+
+func TestNormaliseTrimCaseFold(t *testing.T) {
+	n := NewNormaliser("trim,casefold", "", "", false)
+
+	actual := n.Normalise("  Straße-ABC\t")
+	expected := "strasse-abc"
+
+	if expected != actual {
+		t.Fatalf("Expected %v, got %v\n", expected, actual)
+	}
+}
+
+func TestNormaliseUnicodeForms(t *testing.T) {
+	// The decomposed e + combining acute accent becomes a single code point under NFC
+	nfc := NewNormaliser("nfc", "", "", false)
+
+	if actual := nfc.Normalise("café"); actual != "café" {
+		t.Fatalf("Expected %q, got %q\n", "café", actual)
+	}
+
+	// The full-width characters are only folded to ASCII under NFKC
+	if actual := nfc.Normalise("ｘ１"); actual != "ｘ１" {
+		t.Fatalf("Expected %v, got %v\n", "ｘ１", actual)
+	}
+
+	nfkc := NewNormaliser("nfkc", "", "", false)
+
+	if actual := nfkc.Normalise("ｘ１"); actual != "x1" {
+		t.Fatalf("Expected %v, got %v\n", "x1", actual)
+	}
+
+	// Case folding decomposes j with caron, which the normal form composes again, so the
+	// precomposed and decomposed upper and lower case variants all have the same key
+	nfcFold := NewNormaliser("nfc,casefold", "", "", false)
+
+	for _, entityID := range []string{"\u01f0", "j\u030c", "J\u030c"} {
+		if actual := nfcFold.Normalise(entityID); actual != "\u01f0" {
+			t.Fatalf("Expected %q for %q, got %q\n", "\u01f0", entityID, actual)
+		}
+	}
+}
+
+func TestNormaliseLeadingZeros(t *testing.T) {
+	n := NewNormaliser("zeros", "", "", false)
+
+	cases := map[string]string{
+		"007": "7",
+		"700": "700",
+		"000": "0",
+		"":    "",
+	}
+
+	for input, expected := range cases {
+		if actual := n.Normalise(input); actual != expected {
+			t.Fatalf("Expected %v for %v, got %v\n", expected, input, actual)
+		}
+	}
+}
+
+func TestNormaliseRewrite(t *testing.T) {
+	n := NewNormaliser("trim", `^(?:ID|id)[-_]?(\d+)$`, "$1", false)
+
+	actual := n.Normalise(" ID_123 ")
+	expected := "123"
+
+	if expected != actual {
+		t.Fatalf("Expected %v, got %v\n", expected, actual)
+	}
+}
+
+func TestNormaliseRecordsRawIDs(t *testing.T) {
+	n := NewNormaliser("trim,zeros", "", "", true)
+
+	n.Normalise("007")
+	n.Normalise(" 7")
+	n.Normalise("007")
+	n.Normalise("8")
+
+	expected := []string{" 7", "007"}
+	actual := n.RawIDs("7")
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected %v, got %v\n", expected, actual)
+	}
+}
+
+func TestCalculateConnectedComponentsNormalised(t *testing.T) {
+
+	// Calculate the connected components with normalised IDs and the raw IDs output
+	params := NewParameters("./test/test-3/edge_list.csv", "./test/test-3/actual.csv", ",")
+	params.Normaliser = NewNormaliser("trim,casefold,nfkc,zeros", "", "", true)
+	params.OutputRawIDs = true
+	calculateConnectedComponentsWithParameters(params)
+
+	// Read the actual and expected results
+	if !FilesHaveSameContent("./test/test-3/actual.csv", "./test/test-3/expected.csv") {
+		t.Fatal("Actual results differ from expected results")
+	}
+}
+
+func TestCalculateConnectedComponentsNormalisedJSON(t *testing.T) {
+
+	// Calculate the connected components with the raw IDs of each entity in the JSON output
+	params := NewParameters("./test/test-3/edge_list.csv", "./test/test-3/actual.json", ",")
+	params.Normaliser = NewNormaliser("trim,casefold,nfkc,zeros", "", "", true)
+	params.OutputRawIDs = true
+	params.OutputFormat = "json"
+	calculateConnectedComponentsWithParameters(params)
+
+	// Read the actual and expected results
+	if !FilesHaveSameContent("./test/test-3/actual.json", "./test/test-3/expected.json") {
+		t.Fatal("Actual results differ from expected results")
+	}
+}
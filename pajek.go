@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bufio"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// PajekEdgeSource reads the vertices and edges of a Pajek .net file
+type PajekEdgeSource struct {
+	filepath string
+}
+
+// splitPajekLine splits a line of a Pajek file into fields separated by spaces, keeping quoted
+// labels together without their quotes
+func splitPajekLine(line string) []string {
+
+	fields := []string{}
+	var field strings.Builder
+	inField := false
+	inQuotes := false
+
+	for _, c := range line {
+		switch {
+		case c == '"':
+			inQuotes = !inQuotes
+			inField = true
+		case (c == ' ' || c == '\t') && !inQuotes:
+			if inField {
+				fields = append(fields, field.String())
+				field.Reset()
+				inField = false
+			}
+		default:
+			field.WriteRune(c)
+			inField = true
+		}
+	}
+
+	if inField {
+		fields = append(fields, field.String())
+	}
+
+	return fields
+}
+
+// ReadEdges reads the Pajek file. Each vertex of the *Vertices section is passed as a vertex,
+// identified by its label if it has one and by its number otherwise, and the edges are read from
+// the *Edges, *Arcs, *Edgeslist, *Arcslist and *Matrix sections. The weight after an edge of the
+// *Edges and *Arcs sections, and each value of a *Matrix, is passed as the weight of the edge. Other
+// vertex and edge attributes are ignored, as are any other sections.
+func (s PajekEdgeSource) ReadEdges(handlers EdgeHandlers) {
+
+	// Open the file for reading and ensure it is closed
+	file, err := os.Open(s.filepath)
+	if err != nil {
+		log.Fatal("[!] Couldn't open Pajek file ", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+
+	// Labels of the vertices by their number
+	labels := map[string]string{}
+	numberVertices := 0
+	verticesLineNumber := 0
+
+	// entityID returns the entity ID of a vertex number
+	entityID := func(vertex string) string {
+		if label, present := labels[vertex]; present {
+			return label
+		}
+		return vertex
+	}
+
+	// declareVertices passes every vertex of the *Vertices section once its labels have been read
+	declareVertices := func() {
+		for i := 1; i <= numberVertices; i++ {
+			handlers.Vertex(verticesLineNumber, entityID(strconv.Itoa(i)))
+		}
+		numberVertices = 0
+	}
+
+	section := ""
+	matrixRow := 0
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+
+		// Skip blank lines and comments
+		if len(line) == 0 || strings.HasPrefix(line, "%") {
+			continue
+		}
+
+		fields := splitPajekLine(line)
+
+		// Section headers
+		if strings.HasPrefix(line, "*") {
+			if section == "*vertices" {
+				declareVertices()
+			}
+
+			section = strings.ToLower(fields[0])
+			matrixRow = 0
+
+			if section == "*vertices" {
+				if len(fields) < 2 {
+					log.Fatalf("[!] Error parsing Pajek file: missing number of vertices at line %v\n", lineNumber)
+				}
+				numberVertices, err = strconv.Atoi(fields[1])
+				if err != nil || numberVertices < 0 {
+					log.Fatalf("[!] Error parsing Pajek file: invalid number of vertices at line %v\n", lineNumber)
+				}
+				verticesLineNumber = lineNumber
+			}
+			continue
+		}
+
+		switch section {
+		case "*vertices":
+			if len(fields) >= 2 {
+				labels[fields[0]] = fields[1]
+			}
+
+		case "*edges", "*arcs":
+			if len(fields) < 2 {
+				handlers.ParseError(lineNumber, line, "expected a source and target vertex")
+				continue
+			}
+			// A number after the target vertex is the weight of the edge, before any attributes
+			weight := ""
+			if len(fields) >= 3 {
+				if _, err := strconv.ParseFloat(fields[2], 64); err == nil {
+					weight = fields[2]
+				}
+			}
+			handlers.Edge(lineNumber, []string{entityID(fields[0]), entityID(fields[1])}, weight)
+
+		case "*edgeslist", "*arcslist":
+			if len(fields) < 2 {
+				handlers.ParseError(lineNumber, line, "expected a source vertex and at least one target vertex")
+				continue
+			}
+			for _, target := range fields[1:] {
+				handlers.Edge(lineNumber, []string{entityID(fields[0]), entityID(target)}, "")
+			}
+
+		case "*matrix":
+			// Each row of the adjacency matrix connects its vertex to the columns with non-zero values,
+			// which are the weights of the edges
+			matrixRow++
+			for column, value := range fields {
+				weight, err := strconv.ParseFloat(value, 64)
+				if err != nil {
+					handlers.ParseError(lineNumber, line, "invalid matrix value "+value)
+					break
+				}
+				if weight != 0 {
+					handlers.Edge(lineNumber, []string{entityID(strconv.Itoa(matrixRow)), entityID(strconv.Itoa(column + 1))}, value)
+				}
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Fatal("[!] Error reading Pajek file: ", err)
+	}
+
+	if section == "*vertices" {
+		declareVertices()
+	}
+}
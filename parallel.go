@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"os"
+	"sync"
+)
+
+// csvChunkSize is the number of bytes read from a CSV edge list at a time for the parser goroutines
+const csvChunkSize = 4 << 20
+
+// csvMaxChunkSize is the largest chunk held while looking for the end of a row, beyond which a
+// quoted field is taken to be unterminated
+const csvMaxChunkSize = 256 << 20
+
+// csvChunk is a run of whole rows of a CSV edge list with its position in the file and the number
+// of lines before it
+type csvChunk struct {
+	sequence   int
+	lineOffset int
+	data       []byte
+}
+
+// parsedRow is a row of a chunk once it has been parsed, with its weight taken out, or the text of
+// a row that couldn't be parsed with the reason
+type parsedRow struct {
+	lineNumber int
+	row        []string
+	weight     string
+	text       string
+	reason     string
+	parseError bool
+}
+
+// parsedChunk holds the rows of a chunk once they have been parsed
+type parsedChunk struct {
+	sequence int
+	rows     []parsedRow
+}
+
+// ParallelCSVEdgeSource reads a CSV edge list in the same way as a CSVEdgeSource, with several
+// goroutines parsing chunks of the file
+type ParallelCSVEdgeSource struct {
+	filepath    string
+	weightField int
+	workers     int
+	chunkSize   int
+}
+
+// lastRowEnd returns the position of the last newline in a chunk that ends a row rather than
+// falling within a quoted field, or -1 if there isn't one. The chunk must start at the start of a
+// row. As for the CSV reader, only a quote at the start of a field opens a quoted field, and a pair
+// of quotes within it is an escaped quote.
+func lastRowEnd(data []byte) int {
+
+	rowEnd := -1
+	quoted := false
+
+	for i := 0; i < len(data); {
+		next := bytes.IndexAny(data[i:], "\"\n")
+		if next < 0 {
+			break
+		}
+		i += next
+
+		switch {
+		case data[i] == '\n':
+			if !quoted {
+				rowEnd = i
+			}
+		case quoted && i+1 < len(data) && data[i+1] == '"':
+			i++
+		case quoted:
+			quoted = false
+		case i == 0 || data[i-1] == ',' || data[i-1] == '\n':
+			quoted = true
+		}
+		i++
+	}
+
+	return rowEnd
+}
+
+// readCSVChunks reads a CSV edge list in chunks of whole rows, passing each chunk to the chunks
+// channel once a slot is free in the in-flight channel, and closes the chunks channel at the end
+// of the file
+func readCSVChunks(file io.Reader, chunkSize int, chunks chan<- csvChunk, inFlight chan<- struct{}) {
+
+	defer close(chunks)
+
+	sequence := 0
+	lineOffset := 0
+	buffer := []byte{}
+
+	// send passes on the start of the buffer up to the given length, keeping the rest for the
+	// next chunk
+	send := func(length int) {
+		data := buffer[:length]
+		inFlight <- struct{}{}
+		chunks <- csvChunk{sequence: sequence, lineOffset: lineOffset, data: data}
+
+		sequence++
+		lineOffset += bytes.Count(data, []byte{'\n'})
+		buffer = append(make([]byte, 0, chunkSize+len(buffer)-length), buffer[length:]...)
+	}
+
+	for {
+
+		// Fill the buffer up to the chunk size beyond what was carried over
+		start := len(buffer)
+		buffer = append(buffer, make([]byte, chunkSize)...)
+		n, err := io.ReadFull(file, buffer[start:])
+		buffer = buffer[:start+n]
+
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+
+		if err != nil {
+			log.Fatal("[!] Error reading CSV file: ", err)
+		}
+
+		rowEnd := lastRowEnd(buffer)
+		if rowEnd < 0 {
+			if len(buffer) > csvMaxChunkSize {
+				log.Fatalf("[!] No end of row found within %v bytes of line %v, which may be an unterminated quoted field; use -workers 1 to parse the file on a single goroutine\n",
+					csvMaxChunkSize, lineOffset+1)
+			}
+			continue
+		}
+
+		send(rowEnd + 1)
+	}
+
+	if len(buffer) > 0 {
+		send(len(buffer))
+	}
+}
+
+// ReadEdges reads the rows of the CSV file, passing them to the handlers in the order they were read
+// and on the calling goroutine, so that the rows are handled as they would be by a CSVEdgeSource. At
+// most two chunks per worker are held in memory at once.
+func (s ParallelCSVEdgeSource) ReadEdges(handlers EdgeHandlers) {
+
+	// Open the file for reading and ensure it is closed
+	file, err := os.Open(s.filepath)
+	if err != nil {
+		log.Fatal("[!] Couldn't open CSV file ", err)
+	}
+	defer file.Close()
+
+	chunks := make(chan csvChunk, s.workers)
+	parsed := make(chan parsedChunk, s.workers)
+	inFlight := make(chan struct{}, 2*s.workers)
+
+	go readCSVChunks(file, s.chunkSize, chunks, inFlight)
+
+	// Parse the rows of each chunk
+	var parsers sync.WaitGroup
+	for i := 0; i < s.workers; i++ {
+		parsers.Add(1)
+		go func() {
+			defer parsers.Done()
+
+			for chunk := range chunks {
+				rows := []parsedRow{}
+				parseCSVRows(bytes.NewReader(chunk.data), chunk.lineOffset,
+					func(lineNumber int, row []string) {
+						row, weight := csvEdgeWeight(row, s.weightField)
+						rows = append(rows, parsedRow{lineNumber: lineNumber, row: row, weight: weight})
+					},
+					func(lineNumber int, text string, reason string) {
+						rows = append(rows, parsedRow{lineNumber: lineNumber, text: text, reason: reason, parseError: true})
+					})
+				parsed <- parsedChunk{sequence: chunk.sequence, rows: rows}
+			}
+		}()
+	}
+
+	go func() {
+		parsers.Wait()
+		close(parsed)
+	}()
+
+	// Pass on the rows of each chunk in order, holding chunks parsed ahead of the next one
+	pending := map[int][]parsedRow{}
+	next := 0
+
+	for chunk := range parsed {
+		pending[chunk.sequence] = chunk.rows
+
+		for rows, present := pending[next]; present; rows, present = pending[next] {
+			for _, row := range rows {
+				if row.parseError {
+					handlers.ParseError(row.lineNumber, row.text, row.reason)
+				} else {
+					handlers.Edge(row.lineNumber, row.row, row.weight)
+				}
+			}
+			delete(pending, next)
+			next++
+			<-inFlight
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+)
+
+func TestLastRowEnd(t *testing.T) {
+	chunks := []struct {
+		data   string
+		rowEnd int
+	}{
+		{"", -1},
+		{"a,b", -1},
+		{"a,b\nc,d", 3},
+		{"a,b\nc,d\n", 7},
+		{"a,\"b\nc\",d", -1},
+		{"a,b\n\"c\nd\",e", 3},
+		{"a,\"b\nc\",d\n", 9},
+		{"a,\"b\"\"\nc\n", -1},
+		{"a,\"b\"\"\"\nc\n", 9},
+		{"a\"b,c\nd\n", 7},
+		{"\"a\"b,c\nd\n", 8},
+		{"a,\"b\"", -1},
+	}
+
+	for _, chunk := range chunks {
+		actual := lastRowEnd([]byte(chunk.data))
+
+		if chunk.rowEnd != actual {
+			t.Fatalf("Expected %v, got %v for %q\n", chunk.rowEnd, actual, chunk.data)
+		}
+	}
+}
+
+// readSourceCalls reads an edge source, recording each call to the handlers with its line number
+func readSourceCalls(source EdgeSource) []string {
+
+	calls := []string{}
+
+	source.ReadEdges(EdgeHandlers{
+		Edge: func(lineNumber int, row []string, weight string) {
+			calls = append(calls, fmt.Sprintf("edge %v %q %q", lineNumber, row, weight))
+		},
+		Vertex: func(lineNumber int, entityID string) {
+			calls = append(calls, fmt.Sprintf("vertex %v %q", lineNumber, entityID))
+		},
+		ParseError: func(lineNumber int, text string, reason string) {
+			calls = append(calls, fmt.Sprintf("parse error %v %q %q", lineNumber, text, reason))
+		},
+	})
+
+	return calls
+}
+
+func TestParallelCSVEdgeSource(t *testing.T) {
+
+	// Read the rows on a single goroutine
+	expected := readSourceCalls(CSVEdgeSource{filepath: "./test/quoted.csv", weightField: 3})
+
+	info, err := os.Stat("./test/quoted.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every chunk size, from one byte to the whole file, must give the same rows in the same order
+	for chunkSize := 1; chunkSize <= int(info.Size()); chunkSize++ {
+		actual := readSourceCalls(ParallelCSVEdgeSource{filepath: "./test/quoted.csv", weightField: 3, workers: 3,
+			chunkSize: chunkSize})
+
+		if !reflect.DeepEqual(expected, actual) {
+			t.Fatalf("Expected %v, got %v with a chunk size of %v\n", expected, actual, chunkSize)
+		}
+	}
+}
+
+func TestConnectedComponentsFromFileWorkers(t *testing.T) {
+
+	options := ReadOptions{
+		Normaliser:   NewNormaliser("casefold", "", "", true),
+		ErrorHandler: NewRowErrorHandler(ErrorModeSkip, 0, ""),
+	}
+	expectedStats, expectedCC := connectedComponentsFromFile("./test/quoted.csv", options)
+
+	options.Normaliser = NewNormaliser("casefold", "", "", true)
+	options.Workers = 4
+	actualStats, actualCC := connectedComponentsFromFile("./test/quoted.csv", options)
+
+	if !reflect.DeepEqual(expectedStats, actualStats) {
+		t.Fatalf("Expected %v, got %v\n", expectedStats, actualStats)
+	}
+
+	if !reflect.DeepEqual(expectedCC.vertexToConnectedComponent, actualCC.vertexToConnectedComponent) {
+		t.Fatalf("Expected %v, got %v\n", expectedCC.vertexToConnectedComponent, actualCC.vertexToConnectedComponent)
+	}
+
+	if !reflect.DeepEqual(options.Normaliser.RawIDs("w"), []string{"W"}) {
+		t.Fatalf("Expected %v, got %v\n", []string{"W"}, options.Normaliser.RawIDs("w"))
+	}
+}
+
+// writeBenchmarkEdgeList writes an edge list of random-looking entity IDs for the benchmarks
+func writeBenchmarkEdgeList(b *testing.B) string {
+
+	filepath := path.Join(b.TempDir(), "edge_list.csv")
+	file, err := os.Create(filepath)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	for i := 0; i < 1000000; i++ {
+		fmt.Fprintf(writer, "Entity-%08d,\"Entity-%08d\"\n", (i*7919)%500000, (i*104729)%500000)
+	}
+	writer.Flush()
+
+	return filepath
+}
+
+func BenchmarkConnectedComponentsFromFile(b *testing.B) {
+
+	filepath := writeBenchmarkEdgeList(b)
+
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers-%v", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				connectedComponentsFromFile(filepath, ReadOptions{
+					Normaliser:   NewNormaliser("trim,casefold", "", "", false),
+					ErrorHandler: NewRowErrorHandler(ErrorModeFail, 0, ""),
+					Workers:      workers,
+				})
+			}
+		})
+	}
+}
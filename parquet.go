@@ -0,0 +1,143 @@
+package main
+
+import (
+	"io"
+	"log"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+)
+
+// parseParquetCompression converts the name of a compression codec to its Parquet codec
+func parseParquetCompression(name string) compress.Compression {
+	switch name {
+	case "none":
+		return compress.Codecs.Uncompressed
+	case "snappy":
+		return compress.Codecs.Snappy
+	case "gzip":
+		return compress.Codecs.Gzip
+	case "zstd":
+		return compress.Codecs.Zstd
+	}
+
+	log.Fatalf("[!] Unknown Parquet compression %v, expected one of none, snappy, gzip or zstd\n", name)
+	return compress.Codecs.Uncompressed
+}
+
+// ParquetColumn describes a required column of a Parquet file written by ParquetWriter, which is
+// either a UTF-8 string or a 64-bit integer
+type ParquetColumn struct {
+	Name     string
+	IsString bool
+}
+
+// dataType returns the Arrow type the column is written from
+func (c ParquetColumn) dataType() arrow.DataType {
+	if c.IsString {
+		return arrow.BinaryTypes.String
+	}
+	return arrow.PrimitiveTypes.Int64
+}
+
+// ParquetWriter writes rows to a Parquet file through the Apache Arrow Parquet writer, buffering
+// a row group in memory at a time
+type ParquetWriter struct {
+	writer       *pqarrow.FileWriter
+	builder      *array.RecordBuilder
+	columns      []ParquetColumn
+	rowGroupSize int
+	numRows      int
+}
+
+// NewParquetWriter sets up a writer with the given columns, codec and number of rows per row group
+func NewParquetWriter(w io.Writer, columns []ParquetColumn, codec compress.Compression, rowGroupSize int) *ParquetWriter {
+
+	// Preconditions
+	if len(columns) == 0 {
+		log.Fatal("A Parquet file must have at least one column")
+	}
+
+	if rowGroupSize <= 0 {
+		log.Fatal("The Parquet row group size must be a positive integer")
+	}
+
+	fields := make([]arrow.Field, len(columns))
+	for i, column := range columns {
+		fields[i] = arrow.Field{Name: column.Name, Type: column.dataType()}
+	}
+	schema := arrow.NewSchema(fields, nil)
+
+	properties := parquet.NewWriterProperties(
+		parquet.WithCompression(codec),
+		parquet.WithMaxRowGroupLength(int64(rowGroupSize)),
+		parquet.WithCreatedBy("connected-component"),
+	)
+
+	writer, err := pqarrow.NewFileWriter(schema, w, properties, pqarrow.DefaultWriterProps())
+	if err != nil {
+		log.Fatalf("[!] Unable to set up Parquet writer: %v\n", err)
+	}
+
+	return &ParquetWriter{
+		writer:       writer,
+		builder:      array.NewRecordBuilder(memory.DefaultAllocator, schema),
+		columns:      columns,
+		rowGroupSize: rowGroupSize,
+	}
+}
+
+// WriteRow adds a row, given as a string or int64 for each column, writing the row group if full
+func (p *ParquetWriter) WriteRow(values ...interface{}) {
+
+	// Precondition
+	if len(values) != len(p.columns) {
+		log.Fatalf("Expected %v Parquet values, got %v\n", len(p.columns), len(values))
+	}
+
+	for i, value := range values {
+		if p.columns[i].IsString {
+			p.builder.Field(i).(*array.StringBuilder).Append(value.(string))
+		} else {
+			p.builder.Field(i).(*array.Int64Builder).Append(value.(int64))
+		}
+	}
+
+	p.numRows++
+
+	if p.numRows == p.rowGroupSize {
+		p.writeRowGroup()
+	}
+}
+
+// writeRowGroup writes the buffered rows as a row group
+func (p *ParquetWriter) writeRowGroup() {
+
+	if p.numRows == 0 {
+		return
+	}
+
+	record := p.builder.NewRecordBatch()
+	defer record.Release()
+
+	if err := p.writer.Write(record); err != nil {
+		log.Fatalf("[!] Unable to write Parquet file: %v\n", err)
+	}
+
+	p.numRows = 0
+}
+
+// Close writes any buffered rows and the file footer
+func (p *ParquetWriter) Close() {
+
+	p.writeRowGroup()
+	p.builder.Release()
+
+	if err := p.writer.Close(); err != nil {
+		log.Fatalf("[!] Unable to write Parquet file: %v\n", err)
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/apache/arrow-go/v18/parquet/schema"
+)
+
+// parquetBatchSize is the number of rows read from a Parquet file at a time
+const parquetBatchSize = 64 * 1024
+
+// parquetColumnIndex returns the index of a leaf column of a Parquet schema named by its path,
+// with the names of any enclosing groups separated by dots, or a default index if the name is blank
+func parquetColumnIndex(parquetSchema *schema.Schema, name string, defaultIndex int) int {
+
+	if len(name) == 0 {
+		if defaultIndex < parquetSchema.NumColumns() {
+			return defaultIndex
+		}
+		log.Fatalf("[!] Parquet input has fewer than %v columns\n", defaultIndex+1)
+	}
+
+	index := parquetSchema.ColumnIndexByName(name)
+	if index < 0 {
+		log.Fatalf("[!] Column %q not found in Parquet input\n", name)
+	}
+
+	return index
+}
+
+// parquetLeafValue returns a function giving each value of a leaf column of a record batch read
+// from a Parquet file as a string. The leaf is found by its path through any enclosing structs,
+// and a null struct gives a blank string. Leaves within lists or maps aren't single entity IDs so
+// they aren't supported.
+func parquetLeafValue(record arrow.RecordBatch, path []string) func(int) string {
+
+	indices := record.Schema().FieldIndices(path[0])
+	if len(indices) == 0 {
+		log.Fatalf("[!] Column %q not found in Parquet input\n", path[0])
+	}
+
+	column := record.Column(indices[0])
+	enclosing := []arrow.Array{}
+
+	for _, name := range path[1:] {
+		structColumn, ok := column.(*array.Struct)
+		if !ok {
+			log.Fatalf("[!] Column %v of the Parquet input is within a %v, which is not supported\n",
+				path, column.DataType())
+		}
+
+		index, found := structColumn.DataType().(*arrow.StructType).FieldIdx(name)
+		if !found {
+			log.Fatalf("[!] Column %v not found in Parquet input\n", path)
+		}
+
+		enclosing = append(enclosing, structColumn)
+		column = structColumn.Field(index)
+	}
+
+	return func(i int) string {
+		for _, parent := range enclosing {
+			if parent.IsNull(i) {
+				return ""
+			}
+		}
+		return arrowValueString(column, i)
+	}
+}
+
+// readParquetEdges reads the source and target columns of a Parquet file, a batch of rows at a
+// time, passing each row with its row number and the value of the weight column, if one is named,
+// to the row handler. Blank source and target column names select the first and second leaf columns.
+func readParquetEdges(filepath string, sourceColumn string, targetColumn string, weightColumn string,
+	handleRow func(int, []string, string)) {
+
+	parquetReader, err := file.OpenParquetFile(filepath, false)
+	if err != nil {
+		log.Fatal("[!] Couldn't open Parquet file ", err)
+	}
+	defer parquetReader.Close()
+
+	parquetSchema := parquetReader.MetaData().Schema
+	source := parquetColumnIndex(parquetSchema, sourceColumn, 0)
+	target := parquetColumnIndex(parquetSchema, targetColumn, 1)
+	columns := []int{source, target}
+	if len(weightColumn) > 0 {
+		columns = append(columns, parquetColumnIndex(parquetSchema, weightColumn, 2))
+	}
+
+	reader, err := pqarrow.NewFileReader(parquetReader, pqarrow.ArrowReadProperties{BatchSize: parquetBatchSize},
+		memory.DefaultAllocator)
+	if err != nil {
+		log.Fatal("[!] Couldn't read Parquet file ", err)
+	}
+
+	records, err := reader.GetRecordReader(context.Background(), columns, nil)
+	if err != nil {
+		log.Fatal("[!] Couldn't read Parquet file ", err)
+	}
+	defer records.Release()
+
+	rowNumber := 0
+	for records.Next() {
+		record := records.RecordBatch()
+		values := make([]func(int) string, len(columns))
+		for j, column := range columns {
+			values[j] = parquetLeafValue(record, parquetSchema.Column(column).ColumnPath())
+		}
+
+		for i := 0; i < int(record.NumRows()); i++ {
+			rowNumber++
+			weight := ""
+			if len(values) > 2 {
+				weight = values[2](i)
+			}
+			handleRow(rowNumber, []string{values[0](i), values[1](i)}, weight)
+		}
+	}
+
+	if err := records.Err(); err != nil {
+		log.Fatal("[!] Couldn't read Parquet row group ", err)
+	}
+}
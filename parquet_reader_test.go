@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+)
+
+// writeParquetEdges writes an edge list with named source and target columns to a Parquet file
+func writeParquetEdges(t *testing.T, filepath string, codec compress.Compression, edges [][]string) {
+
+	file, err := os.Create(filepath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	buffered := bufio.NewWriter(file)
+	defer buffered.Flush()
+
+	columns := []ParquetColumn{
+		{Name: "weight", IsString: false},
+		{Name: "source", IsString: true},
+		{Name: "target", IsString: true},
+	}
+
+	writer := NewParquetWriter(buffered, columns, codec, 2)
+	for _, edge := range edges {
+		writer.WriteRow(int64(1), edge[0], edge[1])
+	}
+	writer.Close()
+}
+
+func TestParquetWriterReaderRoundTrip(t *testing.T) {
+	edges := [][]string{{"e-1", "e-2"}, {"e-3", "e-4"}, {"e-2", "e-5"}, {"e-6", "e-6"}, {"e-7", "e-8"}}
+
+	for _, codec := range []string{"none", "snappy", "gzip", "zstd"} {
+
+		filepath := "./test/edges-actual-" + codec + ".parquet"
+		writeParquetEdges(t, filepath, parseParquetCompression(codec), edges)
+
+		actual := [][]string{}
+		readParquetEdges(filepath, "source", "target", "", func(rowNumber int, row []string, weight string) {
+			actual = append(actual, row)
+		})
+
+		reader, err := file.OpenParquetFile(filepath, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		numberRowGroups := reader.NumRowGroups()
+		reader.Close()
+
+		if numberRowGroups != 3 {
+			t.Fatalf("Expected 3 row groups with %v, got %v\n", codec, numberRowGroups)
+		}
+
+		if !reflect.DeepEqual(edges, actual) {
+			t.Fatalf("Expected %v with %v, got %v\n", edges, codec, actual)
+		}
+	}
+}
+
+// readParquetRows returns the rows of the named columns of a Parquet file
+func readParquetRows(filepath string, sourceColumn string, targetColumn string) [][]string {
+	rows := [][]string{}
+	readParquetEdges(filepath, sourceColumn, targetColumn, "", func(rowNumber int, row []string, weight string) {
+		if rowNumber != len(rows)+1 {
+			panic("rows out of order")
+		}
+		rows = append(rows, row)
+	})
+	return rows
+}
+
+// The files in ./test/parquet-testing are from the Apache Parquet test suite, written by Impala
+// and parquet-mr rather than by the Parquet writer here
+func TestReadParquetEdgesDictionary(t *testing.T) {
+
+	// An Impala file with dictionary encoded integer and binary columns over a single row group
+	rows := readParquetRows("./test/parquet-testing/alltypes_plain.parquet", "id", "string_col")
+	expected := [][]string{{"4", "0"}, {"5", "1"}, {"6", "0"}, {"7", "1"}, {"2", "0"}, {"3", "1"}, {"0", "0"}, {"1", "1"}}
+
+	if !reflect.DeepEqual(expected, rows) {
+		t.Fatalf("Expected %v, got %v\n", expected, rows)
+	}
+}
+
+func TestReadParquetEdgesWeightColumn(t *testing.T) {
+
+	// The weight column is a double, read alongside the source and target columns
+	weights := []string{}
+	readParquetEdges("./test/parquet-testing/alltypes_plain.parquet", "id", "string_col", "double_col",
+		func(rowNumber int, row []string, weight string) { weights = append(weights, weight) })
+
+	expected := []string{"0", "10.1", "0", "10.1", "0", "10.1", "0", "10.1"}
+
+	if !reflect.DeepEqual(expected, weights) {
+		t.Fatalf("Expected %v, got %v\n", expected, weights)
+	}
+}
+
+func TestReadParquetEdgesDelta(t *testing.T) {
+
+	// DELTA_BINARY_PACKED integers and DELTA_BYTE_ARRAY strings
+	rows := readParquetRows("./test/parquet-testing/delta_encoding_required_column.parquet", "c_customer_sk:", "c_customer_id:")
+
+	if len(rows) != 100 {
+		t.Fatalf("Expected %v, got %v\n", 100, len(rows))
+	}
+
+	expected := [][]string{{"105", "AAAAAAAAJGAAAAAA"}, {"104", "AAAAAAAAIGAAAAAA"}}
+	if !reflect.DeepEqual(expected, rows[:2]) {
+		t.Fatalf("Expected %v, got %v\n", expected, rows[:2])
+	}
+}
+
+func TestReadParquetEdgesDataPageV2(t *testing.T) {
+
+	// Snappy compressed version 2 data pages with an optional string column holding a null, and
+	// the first two columns selected by default
+	rows := readParquetRows("./test/parquet-testing/datapage_v2.snappy.parquet", "", "")
+	expected := [][]string{{"abc", "1"}, {"abc", "2"}, {"abc", "3"}, {"", "4"}, {"abc", "5"}}
+
+	if !reflect.DeepEqual(expected, rows) {
+		t.Fatalf("Expected %v, got %v\n", expected, rows)
+	}
+}
+
+// writeNestedParquet writes a Parquet file with the source and target entity IDs held in a
+// struct column, the second row of which is null
+func writeNestedParquet(t *testing.T, filepath string) {
+
+	edgeType := arrow.StructOf(
+		arrow.Field{Name: "source", Type: arrow.BinaryTypes.String},
+		arrow.Field{Name: "target", Type: arrow.PrimitiveTypes.Int64},
+	)
+	schema := arrow.NewSchema([]arrow.Field{{Name: "edge", Type: edgeType, Nullable: true}}, nil)
+
+	builder := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer builder.Release()
+
+	edge := builder.Field(0).(*array.StructBuilder)
+	for i, source := range []string{"e-1", "", "e-3"} {
+		if len(source) == 0 {
+			edge.AppendNull()
+			continue
+		}
+		edge.Append(true)
+		edge.FieldBuilder(0).(*array.StringBuilder).Append(source)
+		edge.FieldBuilder(1).(*array.Int64Builder).Append(int64(i + 2))
+	}
+	record := builder.NewRecordBatch()
+	defer record.Release()
+
+	output, err := os.Create(filepath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer output.Close()
+
+	writer, err := pqarrow.NewFileWriter(schema, output, nil, pqarrow.DefaultWriterProps())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Write(record); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadParquetEdgesNested(t *testing.T) {
+
+	writeNestedParquet(t, "./test/edges-actual-nested.parquet")
+
+	// Struct members are named by their dotted path, and a null struct gives blank IDs
+	rows := readParquetRows("./test/edges-actual-nested.parquet", "edge.source", "edge.target")
+	expected := [][]string{{"e-1", "2"}, {"", ""}, {"e-3", "4"}}
+
+	if !reflect.DeepEqual(expected, rows) {
+		t.Fatalf("Expected %v, got %v\n", expected, rows)
+	}
+
+	// Leaf columns are selected by position by default
+	rows = readParquetRows("./test/edges-actual-nested.parquet", "", "")
+	if !reflect.DeepEqual(expected, rows) {
+		t.Fatalf("Expected %v, got %v\n", expected, rows)
+	}
+}
+
+func TestConnectedComponentsFromParquetFile(t *testing.T) {
+
+	// The null entity ID in the fourth row is skipped as a blank ID
+	options := ReadOptions{
+		SourceColumn: "a",
+		TargetColumn: "b",
+		ErrorHandler: NewRowErrorHandler(ErrorModeSkip, 0, ""),
+	}
+	stats, cc := connectedComponentsFromFile("./test/parquet-testing/datapage_v2.snappy.parquet", options)
+
+	if stats.RowsRead != 5 || stats.SkippedRows != 1 {
+		t.Fatalf("Expected 5 rows read and 1 skipped, got %+v\n", *stats)
+	}
+
+	expectedVertexToComponent := map[string]int{
+		"abc": 0,
+		"1":   0,
+		"2":   0,
+		"3":   0,
+		"5":   0,
+	}
+
+	if !reflect.DeepEqual(expectedVertexToComponent, cc.vertexToConnectedComponent) {
+		t.Fatalf("Expected %v, got %v\n", expectedVertexToComponent, cc.vertexToConnectedComponent)
+	}
+}
+
+func TestInputFormat(t *testing.T) {
+	formats := map[string]string{
+		"edges.csv":       "csv",
+		"edges.txt":       "csv",
+		"edges.parquet":   "parquet",
+		"EDGES.PQ":        "parquet",
+		"edges.arrow":     "arrow",
+		"edges.feather":   "arrow",
+		"edges.sqlite":    "sqlite",
+		"dir.v2/edges.gz": "csv",
+	}
+
+	for filepath, expected := range formats {
+		if actual := inputFormat(filepath, ""); actual != expected {
+			t.Fatalf("Expected %v for %v, got %v\n", expected, filepath, actual)
+		}
+	}
+
+	if actual := inputFormat("edges.csv", "parquet"); actual != "parquet" {
+		t.Fatalf("Expected parquet, got %v\n", actual)
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/schema"
+)
+
+func TestParseParquetCompression(t *testing.T) {
+	codecs := map[string]compress.Compression{
+		"none":   compress.Codecs.Uncompressed,
+		"snappy": compress.Codecs.Snappy,
+		"gzip":   compress.Codecs.Gzip,
+		"zstd":   compress.Codecs.Zstd,
+	}
+
+	for name, expected := range codecs {
+		if actual := parseParquetCompression(name); actual != expected {
+			t.Fatalf("Expected %v for %v, got %v\n", expected, name, actual)
+		}
+	}
+}
+
+func TestParquetWriterFileLayout(t *testing.T) {
+	var buffer bytes.Buffer
+
+	columns := []ParquetColumn{
+		{Name: "entity_id", IsString: true},
+		{Name: "component_id", IsString: false},
+	}
+
+	writer := NewParquetWriter(&buffer, columns, compress.Codecs.Snappy, 2)
+	writer.WriteRow("e-1", int64(0))
+	writer.WriteRow("e-2", int64(0))
+	writer.WriteRow("e-3", int64(1))
+	writer.Close()
+
+	reader, err := file.NewParquetReader(bytes.NewReader(buffer.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+
+	// Two row groups are written as the row group size is two rows
+	if reader.NumRowGroups() != 2 {
+		t.Fatalf("Expected 2 row groups, got %v\n", reader.NumRowGroups())
+	}
+
+	if reader.MetaData().RowGroup(0).NumRows() != 2 || reader.MetaData().RowGroup(1).NumRows() != 1 {
+		t.Fatalf("Expected row groups of 2 and 1 rows, got %v and %v\n",
+			reader.MetaData().RowGroup(0).NumRows(), reader.MetaData().RowGroup(1).NumRows())
+	}
+
+	// The columns are required UTF-8 strings or 64-bit integers
+	expectedTypes := []parquet.Type{parquet.Types.ByteArray, parquet.Types.Int64}
+	parquetSchema := reader.MetaData().Schema
+
+	for i, column := range columns {
+		leaf := parquetSchema.Column(i)
+
+		if leaf.Name() != column.Name || leaf.PhysicalType() != expectedTypes[i] || leaf.MaxDefinitionLevel() != 0 {
+			t.Fatalf("Expected required column %v of type %v, got %v\n", column.Name, expectedTypes[i], leaf)
+		}
+	}
+
+	if !parquetSchema.Column(0).LogicalType().Equals(schema.StringLogicalType{}) {
+		t.Fatalf("Expected a string column, got %v\n", parquetSchema.Column(0).LogicalType())
+	}
+
+	chunk, err := reader.MetaData().RowGroup(0).ColumnChunk(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if chunk.Compression() != compress.Codecs.Snappy {
+		t.Fatalf("Expected %v, got %v\n", compress.Codecs.Snappy, chunk.Compression())
+	}
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// DirectedGraph holds a directed graph with the vertices numbered in the order they are first seen
+type DirectedGraph struct {
+	vertexIndex map[string]int
+	vertices    []string
+	successors  [][]int
+}
+
+// NewDirectedGraph sets up a new empty DirectedGraph
+func NewDirectedGraph() *DirectedGraph {
+	return &DirectedGraph{
+		vertexIndex: map[string]int{},
+		vertices:    []string{},
+		successors:  [][]int{},
+	}
+}
+
+// AddVertex adds a vertex if it hasn't been seen before and returns its index
+func (g *DirectedGraph) AddVertex(entityID string) int {
+
+	if index, present := g.vertexIndex[entityID]; present {
+		return index
+	}
+
+	index := len(g.vertices)
+	g.vertexIndex[entityID] = index
+	g.vertices = append(g.vertices, entityID)
+	g.successors = append(g.successors, nil)
+
+	return index
+}
+
+// AddEdge adds an edge from the first entity of the pair to the second
+func (g *DirectedGraph) AddEdge(pair EntityPair) {
+	source := g.AddVertex(pair.EntityID1)
+	target := g.AddVertex(pair.EntityID2)
+	g.successors[source] = append(g.successors[source], target)
+}
+
+// tarjanFrame is a vertex being visited by the depth-first search with the position of the next
+// successor to visit
+type tarjanFrame struct {
+	vertex        int
+	nextSuccessor int
+}
+
+// tarjanSCCs finds the strongly connected components with Tarjan's algorithm, using an explicit
+// stack rather than recursion so that deep graphs can't overflow the call stack. It returns the
+// component of each vertex, numbered in the order the components are completed.
+func (g *DirectedGraph) tarjanSCCs() []int {
+
+	numberVertices := len(g.vertices)
+	index := make([]int, numberVertices)
+	lowLink := make([]int, numberVertices)
+	onStack := make([]bool, numberVertices)
+	component := make([]int, numberVertices)
+
+	for v := range index {
+		index[v] = -1
+	}
+
+	nextIndex := 0
+	numberComponents := 0
+	stack := []int{}
+	callStack := []tarjanFrame{}
+
+	// visit numbers a vertex and puts it on both stacks
+	visit := func(v int) {
+		index[v] = nextIndex
+		lowLink[v] = nextIndex
+		nextIndex++
+		stack = append(stack, v)
+		onStack[v] = true
+		callStack = append(callStack, tarjanFrame{vertex: v})
+	}
+
+	for root := 0; root < numberVertices; root++ {
+
+		if index[root] >= 0 {
+			continue
+		}
+
+		visit(root)
+
+		for len(callStack) > 0 {
+			frame := &callStack[len(callStack)-1]
+			v := frame.vertex
+
+			// Visit the next successor, or update the low link of a successor already on the stack
+			if frame.nextSuccessor < len(g.successors[v]) {
+				w := g.successors[v][frame.nextSuccessor]
+				frame.nextSuccessor++
+
+				if index[w] < 0 {
+					visit(w)
+				} else if onStack[w] {
+					lowLink[v] = min(lowLink[v], index[w])
+				}
+				continue
+			}
+
+			// All successors have been visited, so complete the component if v is its root
+			callStack = callStack[:len(callStack)-1]
+
+			if lowLink[v] == index[v] {
+				for {
+					w := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					onStack[w] = false
+					component[w] = numberComponents
+					if w == v {
+						break
+					}
+				}
+				numberComponents++
+			}
+
+			if len(callStack) > 0 {
+				parent := callStack[len(callStack)-1].vertex
+				lowLink[parent] = min(lowLink[parent], lowLink[v])
+			}
+		}
+	}
+
+	return component
+}
+
+// StronglyConnectedComponents finds the strongly connected components of the graph, numbered in
+// the order their first vertex was seen, with the number of edges within each component
+func (g *DirectedGraph) StronglyConnectedComponents() *ConnectedComponents {
+
+	tarjanComponent := g.tarjanSCCs()
+
+	cc := NewConnectedComponents()
+	renumbered := map[int]int{}
+
+	for v, entityID := range g.vertices {
+
+		component, present := renumbered[tarjanComponent[v]]
+		if !present {
+			component = cc.nextConnectedComponentID
+			renumbered[tarjanComponent[v]] = component
+			cc.nextConnectedComponentID++
+			cc.numberConnectedComponents++
+		}
+
+		cc.vertexToConnectedComponent[entityID] = component
+		cc.connectedComponentToVertices[component] = append(cc.connectedComponentToVertices[component], entityID)
+	}
+
+	for v, successors := range g.successors {
+		for _, w := range successors {
+			if tarjanComponent[v] == tarjanComponent[w] {
+				cc.connectedComponentToEdges[renumbered[tarjanComponent[v]]]++
+			}
+		}
+	}
+
+	return &cc
+}
+
+// condensationHeader builds the header of the condensation file
+func condensationHeader(delimiter string) string {
+
+	// Precondition
+	if len(delimiter) == 0 {
+		log.Fatal("Cannot use a blank delimiter")
+	}
+
+	return "Source Component ID" + delimiter + "Target Component ID" + delimiter + "Edge Count"
+}
+
+// writeCondensationToFile writes the edges of the condensation of a directed graph, the DAG with a
+// vertex per strongly connected component, giving the number of input edges between each pair of
+// components, counted from the edges of the input as they are read again.
+func writeCondensationToFile(cc *ConnectedComponents, params Parameters) {
+
+	log.Printf("Writing condensation DAG to file %v ...\n", params.CondensationFilepath)
+
+	edgeCounts := map[[2]int]int{}
+	rereadEdges(params, func(entityID1 string, entityID2 string) {
+		source := cc.vertexToConnectedComponent[entityID1]
+		target := cc.vertexToConnectedComponent[entityID2]
+		if source != target {
+			edgeCounts[[2]int{source, target}]++
+		}
+	})
+
+	edges := make([][2]int, 0, len(edgeCounts))
+	for edge := range edgeCounts {
+		edges = append(edges, edge)
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i][0] != edges[j][0] {
+			return edges[i][0] < edges[j][0]
+		}
+		return edges[i][1] < edges[j][1]
+	})
+
+	// Open the output CSV file for writing
+	outputFile, err := os.Create(params.CondensationFilepath)
+	if err != nil {
+		log.Fatalf("[!] Unable to open condensation file %v for writing: %v\n", params.CondensationFilepath, err)
+	}
+	defer outputFile.Close()
+
+	bufferedWriter := bufio.NewWriter(outputFile)
+	defer bufferedWriter.Flush()
+
+	fmt.Fprintln(bufferedWriter, condensationHeader(params.OutputDelimiter))
+	for _, edge := range edges {
+		fmt.Fprintln(bufferedWriter, strconv.Itoa(edge[0])+params.OutputDelimiter+strconv.Itoa(edge[1])+
+			params.OutputDelimiter+strconv.Itoa(edgeCounts[edge]))
+	}
+
+	log.Printf("Wrote %v condensation edges\n", len(edges))
+}
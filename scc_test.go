@@ -0,0 +1,97 @@
+package main
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestStronglyConnectedComponents(t *testing.T) {
+
+	graph := NewDirectedGraph()
+	for _, edge := range [][]string{{"a", "b"}, {"b", "c"}, {"c", "a"}, {"c", "d"}, {"d", "e"}, {"e", "d"}, {"f", "g"}, {"d", "h"}} {
+		graph.AddEdge(EntityPair{EntityID1: edge[0], EntityID2: edge[1]})
+	}
+	graph.AddVertex("i")
+
+	cc := graph.StronglyConnectedComponents()
+
+	if cc.numberConnectedComponents != 6 || cc.nextConnectedComponentID != 6 {
+		t.Fatalf("Expected 6 strongly connected components, got %v\n", cc.numberConnectedComponents)
+	}
+
+	expectedComponentToVertices := map[int][]string{
+		0: []string{"a", "b", "c"},
+		1: []string{"d", "e"},
+		2: []string{"f"},
+		3: []string{"g"},
+		4: []string{"h"},
+		5: []string{"i"},
+	}
+
+	if !reflect.DeepEqual(expectedComponentToVertices, cc.connectedComponentToVertices) {
+		t.Fatalf("Expected %v, got %v\n", expectedComponentToVertices, cc.connectedComponentToVertices)
+	}
+
+	expectedComponentToEdges := map[int]int{
+		0: 3,
+		1: 2,
+	}
+
+	if !reflect.DeepEqual(expectedComponentToEdges, cc.connectedComponentToEdges) {
+		t.Fatalf("Expected %v, got %v\n", expectedComponentToEdges, cc.connectedComponentToEdges)
+	}
+}
+
+func TestStronglyConnectedComponentsDeepGraph(t *testing.T) {
+
+	// A cycle of a million vertices would overflow a recursive depth-first search
+	numberVertices := 1000000
+	graph := NewDirectedGraph()
+	for i := 0; i < numberVertices; i++ {
+		graph.AddEdge(EntityPair{EntityID1: strconv.Itoa(i), EntityID2: strconv.Itoa((i + 1) % numberVertices)})
+	}
+	graph.AddEdge(EntityPair{EntityID1: "0", EntityID2: "tail"})
+
+	cc := graph.StronglyConnectedComponents()
+
+	if cc.numberConnectedComponents != 2 {
+		t.Fatalf("Expected 2 strongly connected components, got %v\n", cc.numberConnectedComponents)
+	}
+
+	if len(cc.connectedComponentToVertices[0]) != numberVertices {
+		t.Fatalf("Expected %v vertices in the cycle, got %v\n", numberVertices, len(cc.connectedComponentToVertices[0]))
+	}
+}
+
+func TestCalculateStronglyConnectedComponents(t *testing.T) {
+
+	params := NewParameters("./test/test-7/edge_list.csv", "./test/test-7/actual.csv", ",")
+	params.Directed = true
+	params.CondensationFilepath = "./test/test-7/actual_condensation.csv"
+	calculateConnectedComponentsWithParameters(params)
+
+	if !FilesHaveSameContent("./test/test-7/actual.csv", "./test/test-7/expected.csv") {
+		t.Fatal("Actual results differ from expected results")
+	}
+
+	if !FilesHaveSameContent("./test/test-7/actual_condensation.csv", "./test/test-7/expected_condensation.csv") {
+		t.Fatal("Actual condensation differs from expected condensation")
+	}
+}
+
+func TestDirectedEdgeSet(t *testing.T) {
+	edgeSet := NewDirectedEdgeSet()
+
+	if duplicate, _ := edgeSet.AddWithDirection(EntityPair{EntityID1: "e-1", EntityID2: "e-2"}); duplicate {
+		t.Fatal("Expected a new edge")
+	}
+
+	if duplicate, _ := edgeSet.AddWithDirection(EntityPair{EntityID1: "e-2", EntityID2: "e-1"}); duplicate {
+		t.Fatal("Expected the reversed edge to be a new edge")
+	}
+
+	if duplicate, reversed := edgeSet.AddWithDirection(EntityPair{EntityID1: "e-1", EntityID2: "e-2"}); !duplicate || reversed {
+		t.Fatal("Expected a duplicate edge in the same direction")
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"log"
+)
+
+// SizeFilter selects the connected components whose number of members is in a range, where a
+// maximum size of zero means there is no upper limit
+type SizeFilter struct {
+	MinSize int
+	MaxSize int
+}
+
+// Includes returns whether a component with a number of members is within the size range
+func (f SizeFilter) Includes(size int) bool {
+	return size >= f.MinSize && (f.MaxSize == 0 || size <= f.MaxSize)
+}
+
+// IsActive returns whether the filter excludes any components
+func (f SizeFilter) IsActive() bool {
+	return f.MinSize > 1 || f.MaxSize > 0
+}
+
+// validate stops if the size range isn't valid
+func (f SizeFilter) validate() {
+
+	if f.MinSize < 0 || f.MaxSize < 0 {
+		log.Fatal("The minimum and maximum component sizes must not be negative")
+	}
+
+	if f.MaxSize > 0 && f.MaxSize < f.MinSize {
+		log.Fatalf("The maximum component size %v is smaller than the minimum component size %v\n", f.MaxSize, f.MinSize)
+	}
+}
+
+// filterComponents returns the connected components within the size range, sharing the member
+// slices of the full mapping, with the number of components and vertices filtered out
+func filterComponents(componentToVertices *map[int][]string, filter SizeFilter) (*map[int][]string, int, int) {
+
+	if !filter.IsActive() {
+		return componentToVertices, 0, 0
+	}
+
+	filtered := map[int][]string{}
+	numberComponentsFiltered := 0
+	numberVerticesFiltered := 0
+
+	for component, members := range *componentToVertices {
+		if filter.Includes(len(members)) {
+			filtered[component] = members
+		} else {
+			numberComponentsFiltered++
+			numberVerticesFiltered += len(members)
+		}
+	}
+
+	return &filtered, numberComponentsFiltered, numberVerticesFiltered
+}
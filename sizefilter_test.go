@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestSizeFilterIncludes(t *testing.T) {
+	filters := []struct {
+		filter   SizeFilter
+		size     int
+		expected bool
+	}{
+		{SizeFilter{}, 1, true},
+		{SizeFilter{MinSize: 3}, 2, false},
+		{SizeFilter{MinSize: 3}, 3, true},
+		{SizeFilter{MinSize: 3}, 1000000, true},
+		{SizeFilter{MinSize: 3, MaxSize: 500}, 500, true},
+		{SizeFilter{MinSize: 3, MaxSize: 500}, 501, false},
+	}
+
+	for _, f := range filters {
+		if actual := f.filter.Includes(f.size); actual != f.expected {
+			t.Fatalf("Expected %v for size %v with %+v, got %v\n", f.expected, f.size, f.filter, actual)
+		}
+	}
+}
+
+func TestFilterComponents(t *testing.T) {
+	componentToVertices := map[int][]string{
+		0: []string{"e-1"},
+		1: []string{"e-2", "e-3"},
+		2: []string{"e-4", "e-5", "e-6"},
+	}
+
+	filtered, numberComponents, numberVertices := filterComponents(&componentToVertices, SizeFilter{MinSize: 2, MaxSize: 2})
+
+	expected := map[int][]string{
+		1: []string{"e-2", "e-3"},
+	}
+
+	if !reflect.DeepEqual(expected, *filtered) {
+		t.Fatalf("Expected %v, got %v\n", expected, *filtered)
+	}
+
+	if numberComponents != 2 || numberVertices != 4 {
+		t.Fatalf("Expected 2 components and 4 vertices filtered out, got %v and %v\n", numberComponents, numberVertices)
+	}
+}
+
+func TestCalculateConnectedComponentsSizeFilter(t *testing.T) {
+
+	// Components of 2 and 7 members are filtered out of the output
+	params := NewParameters("./test/test-2/edge_list.csv", "./test/test-2/actual.csv", ",")
+	params.SizeFilter = SizeFilter{MinSize: 3, MaxSize: 4}
+	params.StatsFilepath = "./test/test-2/actual-stats.json"
+	calculateConnectedComponentsWithParameters(params)
+
+	if !FilesHaveSameContent("./test/test-2/actual.csv", "./test/test-2/expected_size_3_4.csv") {
+		t.Fatal("Actual results differ from expected results")
+	}
+
+	contents, err := os.ReadFile(params.StatsFilepath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats := RunStats{}
+	if err := json.Unmarshal(contents, &stats); err != nil {
+		t.Fatal(err)
+	}
+
+	if stats.FilteredComponents != 2 || stats.FilteredVertices != 9 {
+		t.Fatalf("Expected 2 components and 9 vertices filtered out, got %v and %v\n", stats.FilteredComponents,
+			stats.FilteredVertices)
+	}
+
+	// The members output is filtered in the same way
+	params.OutputFormat = "members"
+	params.SizeFilter = SizeFilter{MinSize: 7}
+	calculateConnectedComponentsWithParameters(params)
+
+	contents, err = os.ReadFile("./test/test-2/actual.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "Component ID,Size,Members\n4,7,14|15|16|17|18|19|20\n"
+	if string(contents) != expected {
+		t.Fatalf("Expected %v, got %v\n", expected, string(contents))
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bufio"
+	"container/list"
+	"fmt"
+	"log"
+	"os"
+	"path"
+)
+
+// maxOpenSplitFiles is the number of component files held open while their edges are appended
+const maxOpenSplitFiles = 128
+
+// splitFilename returns the name of the file of a component, or of one part of a bucketed component
+func splitFilename(component int, part int, bucketed bool) string {
+	if bucketed {
+		return fmt.Sprintf("component-%v-part-%v.csv", component, part)
+	}
+	return fmt.Sprintf("component-%v.csv", component)
+}
+
+// splitHeader builds the header of a component file, which gives the type of each row if the
+// edges are written as well as the members
+func splitHeader(delimiter string, writeEdges bool) string {
+
+	// Precondition
+	if len(delimiter) == 0 {
+		log.Fatal("Cannot use a blank delimiter")
+	}
+
+	if writeEdges {
+		return "Type" + delimiter + "Entity ID" + delimiter + "Target Entity ID"
+	}
+	return "Entity ID"
+}
+
+// splitFile is a component file opened for appending edges, with its place in the order of use
+type splitFile struct {
+	file    *os.File
+	writer  *bufio.Writer
+	element *list.Element
+}
+
+// splitFiles appends to the component files, holding a limited number open at a time. When the
+// limit is reached the least recently used file is closed, so the files of the components with
+// the most edges stay open.
+type splitFiles struct {
+	maxOpen int
+	open    map[string]splitFile
+	used    *list.List
+}
+
+// newSplitFiles sets up the component files with at most the given number open at a time
+func newSplitFiles(maxOpen int) *splitFiles {
+
+	// Precondition
+	if maxOpen < 1 {
+		log.Fatal("At least one component file must be held open")
+	}
+
+	return &splitFiles{
+		maxOpen: maxOpen,
+		open:    map[string]splitFile{},
+		used:    list.New(),
+	}
+}
+
+// append writes a line to a component file, closing the least recently used file first if too
+// many are open
+func (s *splitFiles) append(filepath string, line string) {
+
+	f, present := s.open[filepath]
+
+	if present {
+		s.used.MoveToFront(f.element)
+	} else {
+		if len(s.open) == s.maxOpen {
+			s.close(s.used.Back().Value.(string))
+		}
+
+		file, err := os.OpenFile(filepath, os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			log.Fatalf("[!] Unable to open component file %v for writing: %v\n", filepath, err)
+		}
+
+		f = splitFile{file: file, writer: bufio.NewWriter(file), element: s.used.PushFront(filepath)}
+		s.open[filepath] = f
+	}
+
+	fmt.Fprintln(f.writer, line)
+}
+
+// close flushes and closes an open file
+func (s *splitFiles) close(filepath string) {
+
+	f := s.open[filepath]
+	if err := f.writer.Flush(); err != nil {
+		log.Fatalf("[!] Unable to write component file %v: %v\n", filepath, err)
+	}
+	f.file.Close()
+
+	s.used.Remove(f.element)
+	delete(s.open, filepath)
+}
+
+// closeAll flushes and closes the open files
+func (s *splitFiles) closeAll() {
+	for filepath := range s.open {
+		s.close(filepath)
+	}
+}
+
+// writeSplitFile writes the members of a component, or of one part of it, to a new file
+func writeSplitFile(filepath string, members []string, delimiter string, writeEdges bool) {
+
+	outputFile, err := os.Create(filepath)
+	if err != nil {
+		log.Fatalf("[!] Unable to open component file %v for writing: %v\n", filepath, err)
+	}
+	defer outputFile.Close()
+
+	bufferedWriter := bufio.NewWriter(outputFile)
+	defer bufferedWriter.Flush()
+
+	fmt.Fprintln(bufferedWriter, splitHeader(delimiter, writeEdges))
+
+	for _, member := range members {
+		if writeEdges {
+			fmt.Fprintln(bufferedWriter, "vertex"+delimiter+member+delimiter)
+		} else {
+			fmt.Fprintln(bufferedWriter, member)
+		}
+	}
+}
+
+// writeComponentFiles writes the members of each connected component within the size limits to
+// its own file in the split directory, and optionally the edges of the component. Components with
+// more members than the bucket size are split into parts of at most that many members, with each
+// edge written to the part holding its first entity.
+func writeComponentFiles(cc *ConnectedComponents, params Parameters) {
+
+	// Preconditions
+	if params.SplitMinSize < 1 {
+		log.Fatal("The minimum size of a split component must be a positive integer")
+	}
+
+	if params.SplitBucketSize < 0 {
+		log.Fatal("The bucket size of split components must not be negative")
+	}
+
+	log.Printf("Writing component files to directory %v ...\n", params.SplitDirectory)
+
+	if err := os.MkdirAll(params.SplitDirectory, 0755); err != nil {
+		log.Fatalf("[!] Unable to create split directory %v: %v\n", params.SplitDirectory, err)
+	}
+
+	// File of each member of the components written, if edges are written
+	vertexToFile := map[string]string{}
+
+	numberComponentsWritten := 0
+	numberComponentsSkipped := 0
+	numberComponentsBucketed := 0
+
+	for _, component := range *sortedListComponents(&cc.connectedComponentToVertices) {
+
+		members := sortedMembers(cc.connectedComponentToVertices[component])
+
+		if len(members) < params.SplitMinSize {
+			numberComponentsSkipped++
+			continue
+		}
+
+		bucketed := params.SplitBucketSize > 0 && len(members) > params.SplitBucketSize
+		partSize := len(members)
+		if bucketed {
+			partSize = params.SplitBucketSize
+			numberComponentsBucketed++
+		}
+
+		for part := 0; part*partSize < len(members); part++ {
+			partMembers := members[part*partSize : min((part+1)*partSize, len(members))]
+			filepath := path.Join(params.SplitDirectory, splitFilename(component, part, bucketed))
+
+			writeSplitFile(filepath, partMembers, params.OutputDelimiter, params.SplitEdges)
+
+			if params.SplitEdges {
+				for _, member := range partMembers {
+					vertexToFile[member] = filepath
+				}
+			}
+		}
+
+		numberComponentsWritten++
+	}
+
+	// Append the edges read from the input to the file of their first entity
+	if params.SplitEdges {
+		files := newSplitFiles(maxOpenSplitFiles)
+
+		rereadEdges(params, func(entityID1 string, entityID2 string) {
+			if filepath, present := vertexToFile[entityID1]; present {
+				files.append(filepath, "edge"+params.OutputDelimiter+entityID1+params.OutputDelimiter+entityID2)
+			}
+		})
+
+		files.closeAll()
+	}
+
+	log.Printf("Wrote %v component files, skipping %v components smaller than %v and splitting %v components larger than %v\n",
+		numberComponentsWritten, numberComponentsSkipped, params.SplitMinSize, numberComponentsBucketed,
+		params.SplitBucketSize)
+}
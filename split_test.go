@@ -0,0 +1,103 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+// readSplitDirectory returns the contents of each file in a split directory
+func readSplitDirectory(t *testing.T, directory string) map[string]string {
+
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contents := map[string]string{}
+	for _, entry := range entries {
+		content, err := os.ReadFile(directory + "/" + entry.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		contents[entry.Name()] = string(content)
+	}
+
+	return contents
+}
+
+func TestWriteComponentFiles(t *testing.T) {
+
+	// Components with fewer than 4 members aren't written
+	params := NewParameters("./test/test-2/edge_list.csv", "./test/test-2/actual.csv", ",")
+	params.SplitDirectory = "./test/test-2/actual-split"
+	params.SplitMinSize = 4
+	os.RemoveAll(params.SplitDirectory)
+	calculateConnectedComponentsWithParameters(params)
+
+	expected := map[string]string{
+		"component-0.csv": "Entity ID\n1\n2\n3\n4\n",
+		"component-2.csv": "Entity ID\n10\n7\n8\n9\n",
+		"component-4.csv": "Entity ID\n14\n15\n16\n17\n18\n19\n20\n",
+		"component-5.csv": "Entity ID\n21\n22\n23\n24\n",
+	}
+
+	if actual := readSplitDirectory(t, params.SplitDirectory); !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected %v, got %v\n", expected, actual)
+	}
+}
+
+func TestWriteComponentFilesWithEdgesBucketed(t *testing.T) {
+
+	// The component of 5 members is split into parts of at most 3 members, with each edge in the
+	// part of its first entity, and the singletons aren't written
+	params := NewParameters("./test/test-6/edge_list.csv", "./test/test-6/actual.csv", ",")
+	params.VertexFilepath = "./test/test-6/vertices.csv"
+	params.SplitDirectory = "./test/test-6/actual-split"
+	params.SplitEdges = true
+	params.SplitBucketSize = 3
+	os.RemoveAll(params.SplitDirectory)
+	calculateConnectedComponentsWithParameters(params)
+
+	expected := map[string]string{
+		"component-0-part-0.csv": "Type,Entity ID,Target Entity ID\nvertex,a,\nvertex,b,\nvertex,c \"<q>\" & d,\n" +
+			"edge,a,b\nedge,b,c \"<q>\" & d\n",
+		"component-0-part-1.csv": "Type,Entity ID,Target Entity ID\nvertex,f,\nvertex,h\\i\\,\nedge,f,a\nedge,f,h\\i\\\n",
+	}
+
+	if actual := readSplitDirectory(t, params.SplitDirectory); !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected %v, got %v\n", expected, actual)
+	}
+}
+
+func TestSplitFilesLeastRecentlyUsed(t *testing.T) {
+
+	directory := t.TempDir()
+	filepaths := []string{directory + "/a.csv", directory + "/b.csv", directory + "/c.csv"}
+	for _, filepath := range filepaths {
+		if err := os.WriteFile(filepath, []byte{}, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// With two files open, opening c closes b as a was used more recently
+	files := newSplitFiles(2)
+	files.append(filepaths[0], "a1")
+	files.append(filepaths[1], "b1")
+	files.append(filepaths[0], "a2")
+	files.append(filepaths[2], "c1")
+
+	if _, present := files.open[filepaths[1]]; present || len(files.open) != 2 {
+		t.Fatalf("Expected a and c to be open, got %v\n", files.open)
+	}
+
+	files.append(filepaths[1], "b2")
+	files.append(filepaths[0], "a3")
+	files.closeAll()
+
+	expected := map[string]string{"a.csv": "a1\na2\na3\n", "b.csv": "b1\nb2\n", "c.csv": "c1\n"}
+
+	if actual := readSplitDirectory(t, directory); !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected %v, got %v\n", expected, actual)
+	}
+}
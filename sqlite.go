@@ -0,0 +1,246 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// sqliteURI returns the URI opening a SQLite database file in a mode, with the path escaped so
+// that characters such as ?, # and % are read as part of the file name
+func sqliteURI(filepath string, mode string) string {
+	return "file:" + (&url.URL{Path: filepath}).EscapedPath() + "?mode=" + mode
+}
+
+// sqliteBatchSize is the number of rows inserted in each SQLite transaction
+const sqliteBatchSize = 100000
+
+// sqliteSchema creates the tables of the SQLite export, replacing those of a previous export
+var sqliteSchema = []string{
+	"DROP TABLE IF EXISTS vertices",
+	"DROP TABLE IF EXISTS components",
+	"CREATE TABLE vertices (entity_id TEXT NOT NULL PRIMARY KEY, component_id INTEGER NOT NULL)",
+	"CREATE TABLE components (component_id INTEGER NOT NULL PRIMARY KEY, size INTEGER NOT NULL, edge_count INTEGER NOT NULL)",
+}
+
+// sqliteIndexes are created once the rows have been inserted, which is faster than maintaining them
+var sqliteIndexes = []string{
+	"CREATE INDEX vertices_component_id ON vertices (component_id)",
+	"CREATE INDEX components_size ON components (size)",
+}
+
+// sqliteBatchInserter inserts rows with a prepared statement, committing a transaction every
+// sqliteBatchSize rows
+type sqliteBatchInserter struct {
+	db        *sql.DB
+	query     string
+	tx        *sql.Tx
+	stmt      *sql.Stmt
+	batchRows int
+	numRows   int
+}
+
+// newSQLiteBatchInserter sets up an inserter for an insert statement
+func newSQLiteBatchInserter(db *sql.DB, query string) *sqliteBatchInserter {
+	return &sqliteBatchInserter{db: db, query: query}
+}
+
+// Insert adds a row, starting a new transaction if there isn't one open
+func (s *sqliteBatchInserter) Insert(values ...interface{}) {
+
+	if s.tx == nil {
+		tx, err := s.db.Begin()
+		if err != nil {
+			log.Fatalf("[!] Unable to begin SQLite transaction: %v\n", err)
+		}
+
+		stmt, err := tx.Prepare(s.query)
+		if err != nil {
+			log.Fatalf("[!] Unable to prepare SQLite statement: %v\n", err)
+		}
+
+		s.tx = tx
+		s.stmt = stmt
+	}
+
+	if _, err := s.stmt.Exec(values...); err != nil {
+		log.Fatalf("[!] Unable to insert SQLite row: %v\n", err)
+	}
+
+	s.batchRows++
+	s.numRows++
+
+	if s.batchRows == sqliteBatchSize {
+		s.Commit()
+	}
+}
+
+// Commit commits the open transaction, if any
+func (s *sqliteBatchInserter) Commit() {
+
+	if s.tx == nil {
+		return
+	}
+
+	s.stmt.Close()
+	if err := s.tx.Commit(); err != nil {
+		log.Fatalf("[!] Unable to commit SQLite transaction: %v\n", err)
+	}
+
+	s.tx = nil
+	s.stmt = nil
+	s.batchRows = 0
+}
+
+// writeComponentsToSQLite writes the vertex to connected component mapping and the size and number
+// of edges of each connected component to tables of a SQLite database
+func writeComponentsToSQLite(cc *ConnectedComponents, filepath string) {
+
+	log.Printf("Writing results to SQLite database %v ...\n", filepath)
+
+	db, err := sql.Open("sqlite3", sqliteURI(filepath, "rwc"))
+	if err != nil {
+		log.Fatalf("[!] Unable to open SQLite database %v: %v\n", filepath, err)
+	}
+	defer db.Close()
+
+	for _, statement := range sqliteSchema {
+		if _, err := db.Exec(statement); err != nil {
+			log.Fatalf("[!] Unable to create SQLite tables: %v\n", err)
+		}
+	}
+
+	// Vertices, in sorted order
+	vertices := newSQLiteBatchInserter(db, "INSERT INTO vertices (entity_id, component_id) VALUES (?, ?)")
+	for _, vertex := range *sortedListVertices(&cc.vertexToConnectedComponent) {
+		vertices.Insert(vertex, cc.vertexToConnectedComponent[vertex])
+
+		if vertices.numRows%1000000 == 0 {
+			log.Printf("Number of vertices written to SQLite: %v\n", vertices.numRows)
+		}
+	}
+	vertices.Commit()
+
+	// Components, in component ID order
+	components := newSQLiteBatchInserter(db,
+		"INSERT INTO components (component_id, size, edge_count) VALUES (?, ?, ?)")
+	for _, component := range *sortedListComponents(&cc.connectedComponentToVertices) {
+		components.Insert(component, len(cc.connectedComponentToVertices[component]),
+			cc.connectedComponentToEdges[component])
+	}
+	components.Commit()
+
+	for _, statement := range sqliteIndexes {
+		if _, err := db.Exec(statement); err != nil {
+			log.Fatalf("[!] Unable to create SQLite indexes: %v\n", err)
+		}
+	}
+
+	log.Printf("Wrote %v vertices and %v components to SQLite\n", vertices.numRows, components.numRows)
+}
+
+// sqliteColumnIndex returns the index of a named column of a query result, or a default index if
+// the name is blank
+func sqliteColumnIndex(columns []string, name string, defaultIndex int) int {
+
+	if len(name) == 0 {
+		if defaultIndex < len(columns) {
+			return defaultIndex
+		}
+		log.Fatalf("[!] SQLite query returned fewer than %v columns\n", defaultIndex+1)
+	}
+
+	for i, column := range columns {
+		if column == name {
+			return i
+		}
+	}
+
+	log.Fatalf("[!] Column %q not returned by the SQLite query\n", name)
+	return -1
+}
+
+// sqliteValueString returns a value of a query result as a string, with nulls given as blank strings
+func sqliteValueString(value interface{}) string {
+
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	return fmt.Sprint(value)
+}
+
+// readSQLiteEdges runs a query against a SQLite database and passes the source and target columns
+// of each result row with its row number and the value of the weight column, if one is named, to
+// the row handler. Blank source and target column names select the first and second columns.
+func readSQLiteEdges(filepath string, query string, sourceColumn string, targetColumn string, weightColumn string,
+	handleRow func(int, []string, string)) {
+
+	// Precondition
+	if len(strings.TrimSpace(query)) == 0 {
+		log.Fatal("[!] A query is required to read edges from a SQLite database")
+	}
+
+	// Open the database read-only so that the query can't change it
+	db, err := sql.Open("sqlite3", sqliteURI(filepath, "ro"))
+	if err != nil {
+		log.Fatalf("[!] Unable to open SQLite database %v: %v\n", filepath, err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(query)
+	if err != nil {
+		log.Fatalf("[!] Unable to run SQLite query: %v\n", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		log.Fatalf("[!] Unable to read SQLite query columns: %v\n", err)
+	}
+
+	source := sqliteColumnIndex(columns, sourceColumn, 0)
+	target := sqliteColumnIndex(columns, targetColumn, 1)
+	weight := -1
+	if len(weightColumn) > 0 {
+		weight = sqliteColumnIndex(columns, weightColumn, 2)
+	}
+
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	rowNumber := 0
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			log.Fatalf("[!] Unable to read SQLite query row: %v\n", err)
+		}
+
+		rowNumber++
+		weightText := ""
+		if weight >= 0 {
+			weightText = sqliteValueString(values[weight])
+		}
+		handleRow(rowNumber, []string{sqliteValueString(values[source]), sqliteValueString(values[target])}, weightText)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Fatalf("[!] Error reading SQLite query results: %v\n", err)
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"database/sql"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestWriteComponentsToSQLite(t *testing.T) {
+
+	params := NewParameters("./test/test-2/edge_list.csv", "./test/test-2/actual.csv", ",")
+	params.SQLiteFilepath = "./test/test-2/actual.db"
+
+	// Writing twice replaces the tables of the first export
+	calculateConnectedComponentsWithParameters(params)
+	calculateConnectedComponentsWithParameters(params)
+
+	db, err := sql.Open("sqlite3", params.SQLiteFilepath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// Components with their size and number of edges
+	rows, err := db.Query("SELECT component_id, size, edge_count FROM components ORDER BY component_id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	actualComponents := [][]int{}
+	for rows.Next() {
+		var component, size, edgeCount int
+		if err := rows.Scan(&component, &size, &edgeCount); err != nil {
+			t.Fatal(err)
+		}
+		actualComponents = append(actualComponents, []int{component, size, edgeCount})
+	}
+
+	expectedComponents := [][]int{{0, 4, 4}, {1, 2, 1}, {2, 4, 4}, {3, 3, 2}, {4, 7, 7}, {5, 4, 6}}
+
+	if !reflect.DeepEqual(expectedComponents, actualComponents) {
+		t.Fatalf("Expected %v, got %v\n", expectedComponents, actualComponents)
+	}
+
+	// Members of a component, looked up by the component ID index
+	memberRows, err := db.Query("SELECT entity_id FROM vertices WHERE component_id = 2 ORDER BY entity_id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer memberRows.Close()
+
+	actualMembers := []string{}
+	for memberRows.Next() {
+		var entityID string
+		if err := memberRows.Scan(&entityID); err != nil {
+			t.Fatal(err)
+		}
+		actualMembers = append(actualMembers, entityID)
+	}
+
+	expectedMembers := []string{"10", "7", "8", "9"}
+
+	if !reflect.DeepEqual(expectedMembers, actualMembers) {
+		t.Fatalf("Expected %v, got %v\n", expectedMembers, actualMembers)
+	}
+
+	var numberVertices int
+	if err := db.QueryRow("SELECT COUNT(*) FROM vertices").Scan(&numberVertices); err != nil {
+		t.Fatal(err)
+	}
+
+	if numberVertices != 24 {
+		t.Fatalf("Expected 24 vertices, got %v\n", numberVertices)
+	}
+}
+
+func TestConnectedComponentsFromSQLiteQuery(t *testing.T) {
+
+	filepath := "./test/edges-actual.db"
+	os.Remove(filepath)
+
+	db, err := sql.Open("sqlite3", filepath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statements := []string{
+		"CREATE TABLE links (id INTEGER, kind TEXT, src TEXT, dst INTEGER)",
+		"INSERT INTO links VALUES (1, 'a', 'e-1', 2), (2, 'a', 'e-3', 4), (3, 'b', 'e-2', 3)",
+		"INSERT INTO links VALUES (4, 'a', 'e-5', 6), (5, 'a', NULL, 7), (6, 'a', 'e-6', 6)",
+	}
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatal(err)
+		}
+	}
+	db.Close()
+
+	// The query filters out the edge of kind b and the null source entity ID in row 4 is skipped
+	options := ReadOptions{
+		Query:        "SELECT id, src, 'e-' || dst AS dst FROM links WHERE kind = 'a' ORDER BY id",
+		SourceColumn: "src",
+		TargetColumn: "dst",
+		ErrorHandler: NewRowErrorHandler(ErrorModeSkip, 0, ""),
+	}
+	stats, cc := connectedComponentsFromFile(filepath, options)
+
+	if stats.RowsRead != 5 || stats.SkippedRows != 1 {
+		t.Fatalf("Expected 5 rows read and 1 skipped, got %+v\n", *stats)
+	}
+
+	expectedVertexToComponent := map[string]int{
+		"e-1": 0,
+		"e-2": 0,
+		"e-3": 1,
+		"e-4": 1,
+		"e-5": 2,
+		"e-6": 2,
+	}
+
+	if !reflect.DeepEqual(expectedVertexToComponent, cc.vertexToConnectedComponent) {
+		t.Fatalf("Expected %v, got %v\n", expectedVertexToComponent, cc.vertexToConnectedComponent)
+	}
+
+	// Any column of the query can be read as the weight of each edge
+	weights := []string{}
+	readSQLiteEdges(filepath, options.Query, "src", "dst", "id",
+		func(rowNumber int, row []string, weight string) { weights = append(weights, weight) })
+
+	expectedWeights := []string{"1", "2", "4", "5", "6"}
+
+	if !reflect.DeepEqual(expectedWeights, weights) {
+		t.Fatalf("Expected %v, got %v\n", expectedWeights, weights)
+	}
+}
+
+func TestReadSQLiteEdgesEscapedPath(t *testing.T) {
+
+	// Characters with a meaning in a URI are part of the file name
+	filepath := "./test/edges?#%-actual.db"
+	os.Remove(filepath)
+
+	db, err := sql.Open("sqlite3", sqliteURI(filepath, "rwc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("CREATE TABLE links (src TEXT, dst TEXT); INSERT INTO links VALUES ('e-1', 'e-2')"); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	if _, err := os.Stat(filepath); err != nil {
+		t.Fatal(err)
+	}
+
+	rows := [][]string{}
+	readSQLiteEdges(filepath, "SELECT src, dst FROM links", "", "", "",
+		func(rowNumber int, row []string, weight string) { rows = append(rows, row) })
+
+	if !reflect.DeepEqual([][]string{{"e-1", "e-2"}}, rows) {
+		t.Fatalf("Expected %v, got %v\n", [][]string{{"e-1", "e-2"}}, rows)
+	}
+}
+
+func TestSQLiteValueString(t *testing.T) {
+	values := map[interface{}]string{
+		nil:              "",
+		"e-1":            "e-1",
+		int64(-12):       "-12",
+		float64(1.5):     "1.5",
+		float64(3000000): "3000000",
+	}
+
+	for value, expected := range values {
+		if actual := sqliteValueString(value); actual != expected {
+			t.Fatalf("Expected %v, got %v\n", expected, actual)
+		}
+	}
+
+	if actual := sqliteValueString([]byte("e-2")); actual != "e-2" {
+		t.Fatalf("Expected e-2, got %v\n", actual)
+	}
+}
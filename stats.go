@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+)
+
+// RunStats holds the counts of rows, edges and vertices processed during a run
+type RunStats struct {
+	RowsRead              int  `json:"rows_read"`
+	EdgesAccepted         int  `json:"edges_accepted"`
+	DuplicateEdges        int  `json:"duplicate_edges"`
+	ReversedEdges         int  `json:"reversed_edges"`
+	DuplicateEdgesCounted bool `json:"duplicate_edges_counted"`
+	SelfLoops             int  `json:"self_loops"`
+	SkippedRows           int  `json:"skipped_rows"`
+	NewVertices           int  `json:"new_vertices"`
+	Merges                int  `json:"merges"`
+	FilteredComponents    int  `json:"filtered_components"`
+	FilteredVertices      int  `json:"filtered_vertices"`
+}
+
+// recordEdge updates the stats given the outcome of adding an edge
+func (s *RunStats) recordEdge(pair EntityPair, newVertices int, merged bool) {
+
+	s.EdgesAccepted++
+	s.NewVertices += newVertices
+
+	if pair.EntityID1 == pair.EntityID2 {
+		s.SelfLoops++
+	}
+
+	if merged {
+		s.Merges++
+	}
+}
+
+// Log writes the stats to the log
+func (s *RunStats) Log() {
+	log.Printf("Stats - Rows read:       %v\n", s.RowsRead)
+	log.Printf("Stats - Edges accepted:  %v\n", s.EdgesAccepted)
+	if s.DuplicateEdgesCounted {
+		log.Printf("Stats - Duplicate edges: %v\n", s.DuplicateEdges)
+		log.Printf("Stats - Reversed edges:  %v\n", s.ReversedEdges)
+	} else {
+		log.Printf("Stats - Duplicate edges: not counted\n")
+	}
+	log.Printf("Stats - Self-loops:      %v\n", s.SelfLoops)
+	log.Printf("Stats - Skipped rows:    %v\n", s.SkippedRows)
+	log.Printf("Stats - New vertices:    %v\n", s.NewVertices)
+	log.Printf("Stats - Merges:          %v\n", s.Merges)
+	if s.FilteredComponents > 0 {
+		log.Printf("Stats - Filtered out:    %v components with %v vertices\n", s.FilteredComponents, s.FilteredVertices)
+	}
+}
+
+// writeStatsToFile writes the stats to a JSON file
+func writeStatsToFile(stats *RunStats, filepath string) {
+
+	// Open the output JSON file for writing
+	outputFile, err := os.Create(filepath)
+	if err != nil {
+		log.Fatalf("[!] Unable to open stats file %v for writing: %v\n", filepath, err)
+	}
+	defer outputFile.Close()
+
+	encoder := json.NewEncoder(outputFile)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(stats); err != nil {
+		log.Fatalf("[!] Unable to write stats file %v: %v\n", filepath, err)
+	}
+}
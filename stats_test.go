@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestAddEdgeOutcome(t *testing.T) {
+	cc := NewConnectedComponents()
+
+	outcomes := []struct {
+		pair        EntityPair
+		newVertices int
+		merged      bool
+	}{
+		{EntityPair{EntityID1: "e-1", EntityID2: "e-2"}, 2, false},
+		{EntityPair{EntityID1: "e-2", EntityID2: "e-3"}, 1, false},
+		{EntityPair{EntityID1: "e-4", EntityID2: "e-4"}, 1, false},
+		{EntityPair{EntityID1: "e-4", EntityID2: "e-1"}, 0, true},
+		{EntityPair{EntityID1: "e-3", EntityID2: "e-4"}, 0, false},
+	}
+
+	for _, o := range outcomes {
+		newVertices, merged := cc.AddEdge(o.pair)
+
+		if newVertices != o.newVertices || merged != o.merged {
+			t.Fatalf("Expected (%v, %v) for %v, got (%v, %v)\n", o.newVertices, o.merged, o.pair, newVertices, merged)
+		}
+	}
+}
+
+func TestConnectedComponentsFromFileStats(t *testing.T) {
+
+	// Read a file with duplicate and reversed edges, a self-loop, a merge and an invalid row
+	options := ReadOptions{
+		ErrorHandler:    NewRowErrorHandler(ErrorModeSkip, 0, ""),
+		CountDuplicates: true,
+	}
+	stats, _ := connectedComponentsFromFile("./test/stats.csv", options)
+
+	expected := RunStats{
+		RowsRead:              7,
+		EdgesAccepted:         6,
+		DuplicateEdges:        2,
+		ReversedEdges:         1,
+		DuplicateEdgesCounted: true,
+		SelfLoops:             1,
+		SkippedRows:           1,
+		NewVertices:           5,
+		Merges:                1,
+	}
+
+	if !reflect.DeepEqual(expected, *stats) {
+		t.Fatalf("Expected %+v, got %+v\n", expected, *stats)
+	}
+}
+
+func TestVertexRowsStats(t *testing.T) {
+
+	// Each declared vertex of a graph file is a row read, as well as each edge
+	stats, cc := connectedComponentsFromFile("./test/test-5/graph.gml", ReadOptions{})
+
+	if stats.RowsRead != 11 {
+		t.Fatalf("Expected %v, got %v\n", 11, stats.RowsRead)
+	}
+
+	// A rejected row of a vertices file is read as well as skipped
+	if err := os.WriteFile("./test/vertices-actual.csv", []byte("e-1\ne-2,e-3\ne-4\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	verticesFromFile("./test/vertices-actual.csv", cc, ReadOptions{ErrorHandler: NewRowErrorHandler(ErrorModeSkip, 0, "")}, stats)
+
+	if stats.RowsRead != 14 || stats.SkippedRows != 1 {
+		t.Fatalf("Expected 14 rows read and 1 skipped, got %+v\n", *stats)
+	}
+}
+
+func TestWriteStatsToFile(t *testing.T) {
+	expected := RunStats{
+		RowsRead:      3,
+		EdgesAccepted: 2,
+		SkippedRows:   1,
+		NewVertices:   3,
+		Merges:        0,
+	}
+
+	writeStatsToFile(&expected, "./test/stats-actual.json")
+
+	contents, err := os.ReadFile("./test/stats-actual.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual := RunStats{}
+	if err := json.Unmarshal(contents, &actual); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected %+v, got %+v\n", expected, actual)
+	}
+}
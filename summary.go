@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+)
+
+// SummaryColumn is an optional column of the component summary with a value for each component
+type SummaryColumn struct {
+	Header string
+	Values map[int]string
+}
+
+// componentSummaryHeader builds the header of the component summary file
+func componentSummaryHeader(delimiter string, columns []SummaryColumn) string {
+
+	// Precondition
+	if len(delimiter) == 0 {
+		log.Fatal("Cannot use a blank delimiter")
+	}
+
+	header := "Component ID" + delimiter + "Size" + delimiter + "Edge Count"
+	for _, column := range columns {
+		header += delimiter + column.Header
+	}
+
+	return header
+}
+
+// buildComponentSummaryLine builds the line of the component summary file for a component
+func buildComponentSummaryLine(component int, size int, edgeCount int, delimiter string, columns []SummaryColumn) string {
+
+	line := strconv.Itoa(component) + delimiter + strconv.Itoa(size) + delimiter + strconv.Itoa(edgeCount)
+	for _, column := range columns {
+		line += delimiter + column.Values[component]
+	}
+
+	return line
+}
+
+// writeComponentSummary writes one line per connected component within the size range with its
+// size, its number of edges (including duplicates and self-loops) and any summary columns
+func writeComponentSummary(cc *ConnectedComponents, params Parameters, columns []SummaryColumn) {
+
+	log.Printf("Writing component summary to file %v ...\n", params.SummaryFilepath)
+
+	outputFile, err := os.Create(params.SummaryFilepath)
+	if err != nil {
+		log.Fatalf("[!] Unable to open component summary file %v for writing: %v\n", params.SummaryFilepath, err)
+	}
+	defer outputFile.Close()
+
+	bufferedWriter := bufio.NewWriter(outputFile)
+	defer bufferedWriter.Flush()
+
+	fmt.Fprintln(bufferedWriter, componentSummaryHeader(params.OutputDelimiter, columns))
+
+	filteredComponentToVertices, _, _ := filterComponents(&cc.connectedComponentToVertices, params.SizeFilter)
+	for _, component := range *sortedListComponents(filteredComponentToVertices) {
+		fmt.Fprintln(bufferedWriter, buildComponentSummaryLine(component, len(cc.connectedComponentToVertices[component]),
+			cc.connectedComponentToEdges[component], params.OutputDelimiter, columns))
+	}
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestComponentSummaryHeader(t *testing.T) {
+	expected := "Component ID|Size|Edge Count|Max Core"
+	actual := componentSummaryHeader("|", []SummaryColumn{{Header: "Max Core"}})
+
+	if expected != actual {
+		t.Fatalf("Expected %v, got %v\n", expected, actual)
+	}
+}
+
+func TestBuildComponentSummaryLine(t *testing.T) {
+	expected := "4,7,9,3"
+	actual := buildComponentSummaryLine(4, 7, 9, ",", []SummaryColumn{{Header: "Max Core", Values: map[int]string{4: "3"}}})
+
+	if expected != actual {
+		t.Fatalf("Expected %v, got %v\n", expected, actual)
+	}
+}
@@ -0,0 +1,20 @@
+# Written by hand
+Creator "test"
+graph [
+  directed 0
+  node [ id 1 label "first" ]
+  node [ id 2 ]
+  node [ id 3 graphics [ x 1.0 y 2.0 ] ]
+  node [ id 4 ]
+  node [ id 5 ]
+  node [ id 6 ]
+  node [
+    id 7
+    label "isolated
+node"
+  ]
+  edge [ source 1 target 2 weight 3 ]
+  edge [ source 2 target 3 ]
+  edge [ source 4 target 5 ]
+  edge [ source 6 target 6 ]
+]
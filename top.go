@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// VertexDegree is an entity with the number of edges it belongs to
+type VertexDegree struct {
+	EntityID string
+	Degree   int
+}
+
+// TopComponent summarises one of the largest connected components
+type TopComponent struct {
+	Rank               int
+	ComponentID        int
+	Size               int
+	EdgeCount          int
+	SampleMembers      []string
+	HighestDegreeNodes []VertexDegree
+}
+
+// largestComponents returns the IDs of the n largest connected components, largest first, with
+// ties broken by the lower component ID
+func largestComponents(componentToVertices *map[int][]string, n int) []int {
+
+	components := *sortedListComponents(componentToVertices)
+
+	sort.SliceStable(components, func(i, j int) bool {
+		return len((*componentToVertices)[components[i]]) > len((*componentToVertices)[components[j]])
+	})
+
+	if len(components) > n {
+		components = components[:n]
+	}
+
+	return components
+}
+
+// highestDegreeVertices returns up to n members with the highest degree, with ties broken by
+// entity ID
+func highestDegreeVertices(members []string, degrees map[string]int, n int) []VertexDegree {
+
+	vertices := make([]VertexDegree, len(members))
+	for i, member := range members {
+		vertices[i] = VertexDegree{EntityID: member, Degree: degrees[member]}
+	}
+
+	sort.Slice(vertices, func(i, j int) bool {
+		if vertices[i].Degree != vertices[j].Degree {
+			return vertices[i].Degree > vertices[j].Degree
+		}
+		return vertices[i].EntityID < vertices[j].EntityID
+	})
+
+	if len(vertices) > n {
+		vertices = vertices[:n]
+	}
+
+	return vertices
+}
+
+// topComponentsReport summarises the n largest connected components with a sample of their sorted
+// members and their highest-degree members. The degrees of the members are found by reading the
+// input a second time.
+func topComponentsReport(cc *ConnectedComponents, params Parameters) []TopComponent {
+
+	// Preconditions
+	if params.TopN <= 0 {
+		log.Fatal("The number of components in the top components report must be a positive integer")
+	}
+
+	if params.TopSampleSize < 0 {
+		log.Fatal("The number of sample members must not be negative")
+	}
+
+	components := largestComponents(&cc.connectedComponentToVertices, params.TopN)
+
+	// Count the degrees of the members of the largest components only
+	degrees := map[string]int{}
+	for _, component := range components {
+		for _, member := range cc.connectedComponentToVertices[component] {
+			degrees[member] = 0
+		}
+	}
+
+	rereadEdges(params, func(entityID1 string, entityID2 string) {
+		if _, present := degrees[entityID1]; present {
+			degrees[entityID1]++
+			degrees[entityID2]++
+		}
+	})
+
+	report := []TopComponent{}
+	for i, component := range components {
+
+		members := sortedMembers(cc.connectedComponentToVertices[component])
+
+		report = append(report, TopComponent{
+			Rank:               i + 1,
+			ComponentID:        component,
+			Size:               len(members),
+			EdgeCount:          cc.connectedComponentToEdges[component],
+			SampleMembers:      members[:min(params.TopSampleSize, len(members))],
+			HighestDegreeNodes: highestDegreeVertices(members, degrees, params.TopSampleSize),
+		})
+	}
+
+	return report
+}
+
+// topReportHeader builds the header of the top components report
+func topReportHeader(delimiter string) string {
+
+	// Precondition
+	if len(delimiter) == 0 {
+		log.Fatal("Cannot use a blank delimiter")
+	}
+
+	return strings.Join([]string{"Rank", "Component ID", "Size", "Edge Count", "Sample Members",
+		"Highest Degree Members"}, delimiter)
+}
+
+// buildTopReportLine builds a line of the top components report, giving each highest-degree member
+// as its entity ID and degree separated by a colon
+func buildTopReportLine(c TopComponent, delimiter string, memberDelimiter string) string {
+
+	highestDegree := make([]string, len(c.HighestDegreeNodes))
+	for i, vertex := range c.HighestDegreeNodes {
+		highestDegree[i] = vertex.EntityID + ":" + strconv.Itoa(vertex.Degree)
+	}
+
+	return strings.Join([]string{
+		strconv.Itoa(c.Rank),
+		strconv.Itoa(c.ComponentID),
+		strconv.Itoa(c.Size),
+		strconv.Itoa(c.EdgeCount),
+		strings.Join(c.SampleMembers, memberDelimiter),
+		strings.Join(highestDegree, memberDelimiter),
+	}, delimiter)
+}
+
+// writeTopReport writes the top components report
+func writeTopReport(w io.Writer, report []TopComponent, delimiter string, memberDelimiter string) {
+
+	bufferedWriter := bufio.NewWriter(w)
+	defer bufferedWriter.Flush()
+
+	bufferedWriter.WriteString(topReportHeader(delimiter) + "\n")
+	for _, c := range report {
+		bufferedWriter.WriteString(buildTopReportLine(c, delimiter, memberDelimiter) + "\n")
+	}
+}
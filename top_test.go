@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestLargestComponents(t *testing.T) {
+	componentToVertices := map[int][]string{
+		0: []string{"e-1"},
+		1: []string{"e-2", "e-3"},
+		2: []string{"e-4", "e-5"},
+		3: []string{"e-6", "e-7", "e-8"},
+	}
+
+	expected := []int{3, 1, 2}
+
+	if actual := largestComponents(&componentToVertices, 3); !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected %v, got %v\n", expected, actual)
+	}
+
+	expected = []int{3, 1, 2, 0}
+
+	if actual := largestComponents(&componentToVertices, 10); !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected %v, got %v\n", expected, actual)
+	}
+}
+
+func TestTopComponentsReport(t *testing.T) {
+
+	params := NewParameters("./test/test-2/edge_list.csv", "./test/test-2/actual.csv", ",")
+	params.TopN = 3
+	params.TopSampleSize = 3
+
+	_, cc := connectedComponentsFromFile(params.InputFilepath, params.ReadOptions)
+	report := topComponentsReport(cc, params)
+
+	var buffer bytes.Buffer
+	writeTopReport(&buffer, report, ",", "|")
+
+	expected := "Rank,Component ID,Size,Edge Count,Sample Members,Highest Degree Members\n" +
+		"1,4,7,7,14|15|16,19:3|14:2|15:2\n" +
+		"2,0,4,4,1|2|3,2:3|1:2|3:2\n" +
+		"3,2,4,4,10|7|8,10:2|7:2|8:2\n"
+
+	if buffer.String() != expected {
+		t.Fatalf("Expected %v, got %v\n", expected, buffer.String())
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"encoding/csv"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrorMode is the action taken when an invalid row is found in the input
+type ErrorMode int
+
+const (
+	// ErrorModeFail stops the run at the first invalid row
+	ErrorModeFail ErrorMode = iota
+	// ErrorModeSkip counts and skips invalid rows
+	ErrorModeSkip
+	// ErrorModeQuarantine counts and skips invalid rows, writing them to a rejects file
+	ErrorModeQuarantine
+)
+
+// parseErrorMode converts the name of an error mode to an ErrorMode
+func parseErrorMode(mode string) ErrorMode {
+	switch mode {
+	case "fail":
+		return ErrorModeFail
+	case "skip":
+		return ErrorModeSkip
+	case "quarantine":
+		return ErrorModeQuarantine
+	}
+
+	log.Fatalf("[!] Unknown error mode %v, expected one of fail, skip or quarantine\n", mode)
+	return ErrorModeFail
+}
+
+// String returns the name of the error mode
+func (m ErrorMode) String() string {
+	switch m {
+	case ErrorModeSkip:
+		return "skip"
+	case ErrorModeQuarantine:
+		return "quarantine"
+	}
+	return "fail"
+}
+
+// RowErrorHandler applies the error mode to invalid rows and counts them
+type RowErrorHandler struct {
+	mode          ErrorMode
+	maxErrors     int
+	numberErrors  int
+	rejectsFile   *os.File
+	rejectsWriter *csv.Writer
+}
+
+// NewRowErrorHandler sets up a handler for invalid rows; the rejects file is only created in
+// quarantine mode and a maximum number of errors of zero means there is no limit
+func NewRowErrorHandler(mode ErrorMode, maxErrors int, rejectsFilepath string) *RowErrorHandler {
+
+	h := RowErrorHandler{
+		mode:      mode,
+		maxErrors: maxErrors,
+	}
+
+	if mode == ErrorModeQuarantine {
+		file, err := os.Create(rejectsFilepath)
+		if err != nil {
+			log.Fatalf("[!] Unable to open rejects file %v for writing: %v\n", rejectsFilepath, err)
+		}
+		h.rejectsFile = file
+		h.rejectsWriter = csv.NewWriter(file)
+		h.rejectsWriter.Write([]string{"Line Number", "Reason", "Fields"})
+	}
+
+	return &h
+}
+
+// Handle records an invalid row found at a line of the input; a nil handler fails on any error
+func (h *RowErrorHandler) Handle(lineNumber int, row []string, reason string) {
+
+	if h == nil || h.mode == ErrorModeFail {
+		log.Fatalf("[!] Invalid row at line %v (%v): %v\n", lineNumber, reason, row)
+	}
+
+	h.numberErrors++
+
+	if h.mode == ErrorModeQuarantine {
+		record := append([]string{strconv.Itoa(lineNumber), reason}, row...)
+		if err := h.rejectsWriter.Write(record); err != nil {
+			log.Fatalf("[!] Unable to write to rejects file: %v\n", err)
+		}
+	}
+
+	if h.maxErrors > 0 && h.numberErrors > h.maxErrors {
+		h.Close()
+		log.Fatalf("[!] Exceeded the maximum of %v invalid rows\n", h.maxErrors)
+	}
+}
+
+// Close flushes and closes the rejects file if there is one
+func (h *RowErrorHandler) Close() {
+
+	if h == nil || h.rejectsFile == nil {
+		return
+	}
+
+	h.rejectsWriter.Flush()
+	if err := h.rejectsWriter.Error(); err != nil {
+		log.Fatalf("[!] Unable to write to rejects file: %v\n", err)
+	}
+
+	h.rejectsFile.Close()
+	h.rejectsFile = nil
+}
+
+// validateVertexRow returns the reason a row of the vertices file is invalid or a blank string if
+// it is valid
+func validateVertexRow(row []string) string {
+
+	if len(row) != 1 {
+		return "expected 1 field but found " + strconv.Itoa(len(row))
+	}
+
+	return validateEntityID(row[0])
+}
+
+// validateEntityID returns the reason an entity ID is invalid or a blank string if it is valid
+func validateEntityID(entityID string) string {
+
+	if !utf8.ValidString(entityID) {
+		return "invalid UTF-8"
+	}
+
+	if len(strings.TrimSpace(entityID)) == 0 {
+		return "blank entity ID"
+	}
+
+	return ""
+}
+
+// validateRow returns the reason a row of the edge list is invalid or a blank string if it is valid
+func validateRow(row []string) string {
+
+	if len(row) != 2 {
+		return "expected 2 fields but found " + strconv.Itoa(len(row))
+	}
+
+	for _, field := range row {
+		if reason := validateEntityID(field); len(reason) > 0 {
+			return reason
+		}
+	}
+
+	return ""
+}
+
+// parseEdgeWeight returns the weight of an edge given as text, which is 1 if the input gives none,
+// with the reason it is invalid or a blank string if it is valid. A weight must be a finite number.
+func parseEdgeWeight(weight string) (float64, string) {
+
+	weight = strings.TrimSpace(weight)
+	if len(weight) == 0 {
+		return 1, ""
+	}
+
+	value, err := strconv.ParseFloat(weight, 64)
+	if err != nil || math.IsInf(value, 0) || math.IsNaN(value) {
+		return 0, "invalid edge weight " + weight
+	}
+
+	return value, ""
+}
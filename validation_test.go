@@ -0,0 +1,148 @@
+package main
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParseErrorMode(t *testing.T) {
+	modes := map[string]ErrorMode{
+		"fail":       ErrorModeFail,
+		"skip":       ErrorModeSkip,
+		"quarantine": ErrorModeQuarantine,
+	}
+
+	for name, expected := range modes {
+		actual := parseErrorMode(name)
+
+		if expected != actual {
+			t.Fatalf("Expected %v, got %v\n", expected, actual)
+		}
+
+		if actual.String() != name {
+			t.Fatalf("Expected %v, got %v\n", name, actual.String())
+		}
+	}
+}
+
+func TestValidateRow(t *testing.T) {
+	rows := []struct {
+		row    []string
+		reason string
+	}{
+		{[]string{"e-1", "e-2"}, ""},
+		{[]string{"e-1"}, "expected 2 fields but found 1"},
+		{[]string{"e-1", "e-2", "e-3"}, "expected 2 fields but found 3"},
+		{[]string{"e-1", " "}, "blank entity ID"},
+		{[]string{"e-\xff", "e-2"}, "invalid UTF-8"},
+	}
+
+	for _, r := range rows {
+		actual := validateRow(r.row)
+
+		if r.reason != actual {
+			t.Fatalf("Expected %q for %v, got %q\n", r.reason, r.row, actual)
+		}
+	}
+}
+
+func TestParseEdgeWeight(t *testing.T) {
+	weights := []struct {
+		weight   string
+		expected float64
+		reason   string
+	}{
+		{"", 1, ""},
+		{"2.5", 2.5, ""},
+		{"-3", -3, ""},
+		{"0", 0, ""},
+		{"heavy", 0, "invalid edge weight heavy"},
+		{"NaN", 0, "invalid edge weight NaN"},
+		{"Inf", 0, "invalid edge weight Inf"},
+	}
+
+	for _, w := range weights {
+		actual, reason := parseEdgeWeight(w.weight)
+
+		if w.reason != reason {
+			t.Fatalf("Expected %q for %q, got %q\n", w.reason, w.weight, reason)
+		}
+
+		if len(reason) == 0 && w.expected != actual {
+			t.Fatalf("Expected %v for %q, got %v\n", w.expected, w.weight, actual)
+		}
+	}
+}
+
+func TestConnectedComponentsFromFileSkip(t *testing.T) {
+
+	// Calculate connected components in a file with invalid rows
+	errorHandler := NewRowErrorHandler(ErrorModeSkip, 0, "")
+	stats, cc := connectedComponentsFromFile("./test/invalid.csv", ReadOptions{ErrorHandler: errorHandler})
+
+	if stats.SkippedRows != 5 {
+		t.Fatalf("Expected 5 invalid rows, got %v\n", stats.SkippedRows)
+	}
+
+	// Check the vertex to connected component assignment
+	expectedVertexToComponent := map[string]int{
+		"e-1":  0,
+		"e-2":  0,
+		"e-11": 0,
+	}
+
+	if !reflect.DeepEqual(expectedVertexToComponent, cc.vertexToConnectedComponent) {
+		t.Fatalf("Expected %v, got %v\n", expectedVertexToComponent, cc.vertexToConnectedComponent)
+	}
+}
+
+func TestConnectedComponentsFromFileQuarantine(t *testing.T) {
+
+	// Calculate connected components in a file with invalid rows
+	errorHandler := NewRowErrorHandler(ErrorModeQuarantine, 0, "./test/rejects-actual.csv")
+	connectedComponentsFromFile("./test/invalid.csv", ReadOptions{ErrorHandler: errorHandler})
+	errorHandler.Close()
+
+	expected := []string{
+		"Line Number,Reason,Fields",
+		"2,expected 2 fields but found 1,e-3",
+		"3,blank entity ID,e-4,",
+		"4,invalid UTF-8,e-5,e-\xff6",
+		"5,expected 2 fields but found 3,e-7,e-8,e-9",
+		`6,"bare "" in non-quoted-field","e-1""x,e-10"`,
+	}
+
+	actual := ReadFileIntoSlice("./test/rejects-actual.csv")
+
+	if !reflect.DeepEqual(expected, *actual) {
+		t.Fatalf("Expected %q, got %q\n", expected, *actual)
+	}
+}
+
+func TestParseCSVRowsParseErrorText(t *testing.T) {
+
+	// A row with a quoted field spanning lines that can't be parsed, between valid rows
+	input := "a,b\n\"c\nd\"e,f\r\ng,h\ni\"j,k"
+
+	rows := [][]string{}
+	parseErrors := []string{}
+	parseCSVRows(strings.NewReader(input), 10,
+		func(lineNumber int, row []string) {
+			rows = append(rows, append([]string{strconv.Itoa(lineNumber)}, row...))
+		},
+		func(lineNumber int, text string, reason string) {
+			parseErrors = append(parseErrors, strconv.Itoa(lineNumber)+":"+text)
+		})
+
+	expectedRows := [][]string{{"11", "a", "b"}, {"14", "g", "h"}}
+	if !reflect.DeepEqual(expectedRows, rows) {
+		t.Fatalf("Expected %q, got %q\n", expectedRows, rows)
+	}
+
+	expectedParseErrors := []string{"12:\"c\nd\"e,f", "15:i\"j,k"}
+	if !reflect.DeepEqual(expectedParseErrors, parseErrors) {
+		t.Fatalf("Expected %q, got %q\n", expectedParseErrors, parseErrors)
+	}
+}
@@ -0,0 +1,381 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"log"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+)
+
+// VertexWriter writes the vertex to connected component mapping in an output format. The
+// vertices are given in sorted order and the raw ID is blank unless raw IDs are output.
+type VertexWriter interface {
+	WriteHeader()
+	WriteVertex(entityID string, rawID string, component int)
+	Flush()
+}
+
+// VertexColumn is an optional output column with a value for each vertex, either a string or an
+// integer, written after the other columns of the vertex output formats
+type VertexColumn struct {
+	Header   string
+	Name     string
+	Strings  map[string]string
+	Integers map[string]int
+}
+
+// isString returns whether the column holds strings rather than integers
+func (c VertexColumn) isString() bool {
+	return c.Strings != nil
+}
+
+// text returns the value of the column for a vertex as text
+func (c VertexColumn) text(entityID string) string {
+	if c.isString() {
+		return c.Strings[entityID]
+	}
+	return strconv.Itoa(c.Integers[entityID])
+}
+
+// value returns the value of the column for a vertex as a string or an int64
+func (c VertexColumn) value(entityID string) interface{} {
+	if c.isString() {
+		return c.Strings[entityID]
+	}
+	return int64(c.Integers[entityID])
+}
+
+// hierarchicalColumn builds the output column giving each vertex a hierarchical ID made up of its
+// component ID and the number of its group within the component, with the groups numbered in the
+// order the members of the component were first seen. Members without a group are groups on their
+// own. It also returns the number of groups.
+func hierarchicalColumn(
+	cc *ConnectedComponents,
+	header string,
+	name string,
+	groupOf func(entityID string) (int, bool)) (VertexColumn, int) {
+
+	column := VertexColumn{Header: header, Name: name, Strings: map[string]string{}}
+	numberGroups := 0
+
+	for component, members := range cc.connectedComponentToVertices {
+		groupNumber := map[int]int{}
+		numberUngrouped := 0
+
+		for _, member := range members {
+			group, grouped := groupOf(member)
+
+			number, present := groupNumber[group]
+			if !grouped || !present {
+				number = len(groupNumber) + numberUngrouped
+			}
+			if grouped && !present {
+				groupNumber[group] = number
+			} else if !grouped {
+				numberUngrouped++
+			}
+
+			column.Strings[member] = strconv.Itoa(component) + "." + strconv.Itoa(number)
+		}
+
+		numberGroups += len(groupNumber) + numberUngrouped
+	}
+
+	return column, numberGroups
+}
+
+// WriterOptions holds the settings of the vertex writers; the raw IDs are output if a
+// normaliser holding them is given
+type WriterOptions struct {
+	Delimiter           string
+	RawIDs              *Normaliser
+	ComponentToVertices *map[int][]string
+	ParquetCompression  string
+	ParquetRowGroupSize int
+	SizeFilter          SizeFilter
+	VertexColumns       []VertexColumn
+}
+
+// newVertexWriter sets up the VertexWriter for an output format
+func newVertexWriter(format string, w io.Writer, options WriterOptions) VertexWriter {
+
+	outputRawIDs := options.RawIDs != nil
+
+	switch format {
+	case "vertices":
+		delimiter, valid := delimiterRune(options.Delimiter)
+		if !valid {
+			log.Fatalf("The delimiter of the vertices output format must be a single character other than a quote or line break, found %q\n", options.Delimiter)
+		}
+		writer := csv.NewWriter(w)
+		writer.Comma = delimiter
+		return &DelimitedVertexWriter{writer: writer, outputRawIDs: outputRawIDs, columns: options.VertexColumns}
+	case "jsonl":
+		return &JSONLinesVertexWriter{w: w, encoder: json.NewEncoder(w), columns: options.VertexColumns}
+	case "json":
+		return &JSONVertexWriter{w: w, outputRawIDs: outputRawIDs, componentIndex: map[int]int{},
+			columns: options.VertexColumns}
+	case "parquet":
+		return NewParquetVertexWriter(w, options)
+	}
+
+	log.Fatalf("[!] Unknown vertex output format: %v\n", format)
+	return nil
+}
+
+// delimiterRune returns the delimiter of a CSV file as a rune, and whether it is a single character
+// that can separate CSV fields, which excludes quotes and line breaks
+func delimiterRune(delimiter string) (rune, bool) {
+
+	r, size := utf8.DecodeRuneInString(delimiter)
+	if size == 0 || size != len(delimiter) || r == utf8.RuneError || r == '"' || r == '\r' || r == '\n' {
+		return r, false
+	}
+
+	return r, true
+}
+
+// DelimitedVertexWriter writes one delimited line per vertex (or raw ID) with a header, quoting
+// any field holding the delimiter, a quote or a line break
+type DelimitedVertexWriter struct {
+	writer       *csv.Writer
+	outputRawIDs bool
+	columns      []VertexColumn
+}
+
+// WriteHeader writes the header line
+func (d *DelimitedVertexWriter) WriteHeader() {
+
+	header := []string{"Entity ID", "Component ID"}
+	if d.outputRawIDs {
+		header = append(header, "Raw Entity ID")
+	}
+
+	for _, column := range d.columns {
+		header = append(header, column.Header)
+	}
+
+	d.write(header)
+}
+
+// WriteVertex writes the line for a vertex
+func (d *DelimitedVertexWriter) WriteVertex(entityID string, rawID string, component int) {
+
+	line := []string{entityID, strconv.Itoa(component)}
+	if d.outputRawIDs {
+		line = append(line, rawID)
+	}
+
+	for _, column := range d.columns {
+		line = append(line, column.text(entityID))
+	}
+
+	d.write(line)
+}
+
+// write writes a line of fields
+func (d *DelimitedVertexWriter) write(fields []string) {
+	if err := d.writer.Write(fields); err != nil {
+		log.Fatalf("[!] Unable to write output file: %v\n", err)
+	}
+}
+
+// Flush writes any buffered lines
+func (d *DelimitedVertexWriter) Flush() {
+	d.writer.Flush()
+	if err := d.writer.Error(); err != nil {
+		log.Fatalf("[!] Unable to write output file: %v\n", err)
+	}
+}
+
+// JSONLinesVertex is the JSON Lines representation of a vertex and its connected component
+type JSONLinesVertex struct {
+	EntityID    string `json:"entity_id"`
+	RawEntityID string `json:"raw_entity_id,omitempty"`
+	ComponentID int    `json:"component_id"`
+}
+
+// JSONLinesVertexWriter writes one JSON object per vertex (or raw ID)
+type JSONLinesVertexWriter struct {
+	w       io.Writer
+	encoder *json.Encoder
+	columns []VertexColumn
+}
+
+// WriteHeader does nothing as JSON Lines files don't have a header
+func (j *JSONLinesVertexWriter) WriteHeader() {}
+
+// WriteVertex writes the JSON object for a vertex
+func (j *JSONLinesVertexWriter) WriteVertex(entityID string, rawID string, component int) {
+	vertex := JSONLinesVertex{
+		EntityID:    entityID,
+		RawEntityID: rawID,
+		ComponentID: component,
+	}
+
+	if len(j.columns) == 0 {
+		if err := j.encoder.Encode(vertex); err != nil {
+			log.Fatalf("[!] Unable to write vertex %v: %v\n", entityID, err)
+		}
+		return
+	}
+
+	// Add the vertex columns to the end of the object, keeping the order of the fields
+	object, err := json.Marshal(vertex)
+	if err != nil {
+		log.Fatalf("[!] Unable to write vertex %v: %v\n", entityID, err)
+	}
+
+	var line bytes.Buffer
+	line.Write(object[:len(object)-1])
+	for _, column := range j.columns {
+		name, _ := json.Marshal(column.Name)
+		value, _ := json.Marshal(column.value(entityID))
+		line.WriteString("," + string(name) + ":" + string(value))
+	}
+	line.WriteString("}\n")
+
+	if _, err := j.w.Write(line.Bytes()); err != nil {
+		log.Fatalf("[!] Unable to write vertex %v: %v\n", entityID, err)
+	}
+}
+
+// Flush does nothing as each object is written immediately
+func (j *JSONLinesVertexWriter) Flush() {}
+
+// JSONComponent is the JSON representation of a connected component and its entity IDs, with the
+// raw IDs of each entity and the values of any vertex columns in the same order as the entity IDs
+type JSONComponent struct {
+	ComponentID   int                      `json:"component_id"`
+	EntityIDs     []string                 `json:"entity_ids"`
+	RawEntityIDs  [][]string               `json:"raw_entity_ids,omitempty"`
+	VertexColumns map[string][]interface{} `json:"vertex_columns,omitempty"`
+}
+
+// JSONVertexWriter writes a single JSON array of the connected components, each holding its
+// entity IDs in sorted order. The components are held in memory until the writer is flushed.
+type JSONVertexWriter struct {
+	w              io.Writer
+	outputRawIDs   bool
+	components     []JSONComponent
+	componentIndex map[int]int
+	columns        []VertexColumn
+}
+
+// WriteHeader does nothing as the components are written on flush
+func (j *JSONVertexWriter) WriteHeader() {}
+
+// WriteVertex adds a vertex (or raw ID) to its connected component
+func (j *JSONVertexWriter) WriteVertex(entityID string, rawID string, component int) {
+
+	index, present := j.componentIndex[component]
+	if !present {
+		index = len(j.components)
+		j.componentIndex[component] = index
+		j.components = append(j.components, JSONComponent{ComponentID: component, EntityIDs: []string{}})
+	}
+
+	c := &j.components[index]
+
+	// A vertex with several raw IDs is given once for each raw ID, so only add it the first time
+	if n := len(c.EntityIDs); n == 0 || c.EntityIDs[n-1] != entityID {
+		c.EntityIDs = append(c.EntityIDs, entityID)
+
+		if j.outputRawIDs {
+			c.RawEntityIDs = append(c.RawEntityIDs, []string{})
+		}
+
+		if len(j.columns) > 0 && c.VertexColumns == nil {
+			c.VertexColumns = map[string][]interface{}{}
+		}
+		for _, column := range j.columns {
+			c.VertexColumns[column.Name] = append(c.VertexColumns[column.Name], column.value(entityID))
+		}
+	}
+
+	if j.outputRawIDs {
+		last := len(c.RawEntityIDs) - 1
+		c.RawEntityIDs[last] = append(c.RawEntityIDs[last], rawID)
+	}
+}
+
+// Flush writes the connected components in order of their IDs
+func (j *JSONVertexWriter) Flush() {
+
+	sort.Slice(j.components, func(a, b int) bool {
+		return j.components[a].ComponentID < j.components[b].ComponentID
+	})
+
+	encoder := json.NewEncoder(j.w)
+	if err := encoder.Encode(j.components); err != nil {
+		log.Fatalf("[!] Unable to write components: %v\n", err)
+	}
+}
+
+// ParquetVertexWriter writes one Parquet row per vertex (or raw ID) with the size of its connected
+// component
+type ParquetVertexWriter struct {
+	writer              *ParquetWriter
+	outputRawIDs        bool
+	componentToVertices *map[int][]string
+	columns             []VertexColumn
+}
+
+// NewParquetVertexWriter sets up a Parquet writer with the vertex schema
+func NewParquetVertexWriter(w io.Writer, options WriterOptions) *ParquetVertexWriter {
+
+	// Precondition
+	if options.ComponentToVertices == nil {
+		log.Fatal("The Parquet writer requires the connected component to vertices mapping")
+	}
+
+	columns := []ParquetColumn{
+		{Name: "entity_id", IsString: true},
+		{Name: "component_id", IsString: false},
+		{Name: "component_size", IsString: false},
+	}
+
+	if options.RawIDs != nil {
+		columns = append(columns, ParquetColumn{Name: "raw_entity_id", IsString: true})
+	}
+
+	for _, column := range options.VertexColumns {
+		columns = append(columns, ParquetColumn{Name: column.Name, IsString: column.isString()})
+	}
+
+	return &ParquetVertexWriter{
+		writer: NewParquetWriter(w, columns, parseParquetCompression(options.ParquetCompression),
+			options.ParquetRowGroupSize),
+		outputRawIDs:        options.RawIDs != nil,
+		componentToVertices: options.ComponentToVertices,
+		columns:             options.VertexColumns,
+	}
+}
+
+// WriteHeader does nothing as the schema is written in the footer
+func (p *ParquetVertexWriter) WriteHeader() {}
+
+// WriteVertex adds the row for a vertex
+func (p *ParquetVertexWriter) WriteVertex(entityID string, rawID string, component int) {
+
+	values := []interface{}{entityID, int64(component), int64(len((*p.componentToVertices)[component]))}
+
+	if p.outputRawIDs {
+		values = append(values, rawID)
+	}
+
+	for _, column := range p.columns {
+		values = append(values, column.value(entityID))
+	}
+
+	p.writer.WriteRow(values...)
+}
+
+// Flush writes the remaining rows and the file footer
+func (p *ParquetVertexWriter) Flush() {
+	p.writer.Close()
+}
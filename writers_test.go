@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+// writeVertices writes a fixed set of vertices with a VertexWriter to a buffer
+func writeVertices(format string, delimiter string, outputRawIDs bool) string {
+	var buffer bytes.Buffer
+
+	options := WriterOptions{Delimiter: delimiter}
+	if outputRawIDs {
+		options.RawIDs = NewNormaliser("", "", "", true)
+	}
+
+	writer := newVertexWriter(format, &buffer, options)
+	writer.WriteHeader()
+	writer.WriteVertex("e-1", "E-1", 1)
+	writer.WriteVertex("e-2", "E-2", 0)
+	writer.WriteVertex("e-2", "e-02", 0)
+	writer.Flush()
+
+	return buffer.String()
+}
+
+func TestDelimitedVertexWriter(t *testing.T) {
+	actual := writeVertices("vertices", "|", false)
+	expected := "Entity ID|Component ID\ne-1|1\ne-2|0\ne-2|0\n"
+
+	if expected != actual {
+		t.Fatalf("Expected %q, got %q\n", expected, actual)
+	}
+}
+
+func TestDelimitedVertexWriterRawIDs(t *testing.T) {
+	actual := writeVertices("vertices", ",", true)
+	expected := "Entity ID,Component ID,Raw Entity ID\ne-1,1,E-1\ne-2,0,E-2\ne-2,0,e-02\n"
+
+	if expected != actual {
+		t.Fatalf("Expected %q, got %q\n", expected, actual)
+	}
+}
+
+func TestDelimitedVertexWriterQuoting(t *testing.T) {
+	var buffer bytes.Buffer
+
+	// IDs holding the delimiter, a quote or a line break are quoted so the file can be read back
+	writer := newVertexWriter("vertices", &buffer, WriterOptions{Delimiter: ";", RawIDs: NewNormaliser("", "", "", true)})
+	writer.WriteHeader()
+	writer.WriteVertex("a;b", "A;B", 0)
+	writer.WriteVertex("c", "\"C\"\n", 1)
+	writer.Flush()
+
+	actual := buffer.String()
+	expected := "Entity ID;Component ID;Raw Entity ID\n\"a;b\";0;\"A;B\"\nc;1;\"\"\"C\"\"\n\"\n"
+
+	if expected != actual {
+		t.Fatalf("Expected %q, got %q\n", expected, actual)
+	}
+}
+
+func TestJSONLinesVertexWriter(t *testing.T) {
+	var buffer bytes.Buffer
+
+	writer := newVertexWriter("jsonl", &buffer, WriterOptions{Delimiter: ","})
+	writer.WriteHeader()
+	writer.WriteVertex("e-1", "", 1)
+	writer.WriteVertex("e-2", "", 0)
+	writer.Flush()
+
+	actual := buffer.String()
+	expected := `{"entity_id":"e-1","component_id":1}` + "\n" + `{"entity_id":"e-2","component_id":0}` + "\n"
+
+	if expected != actual {
+		t.Fatalf("Expected %q, got %q\n", expected, actual)
+	}
+}
+
+// writeVerticesWithColumns writes a fixed set of vertices with a string and an integer vertex
+// column to a buffer
+func writeVerticesWithColumns(format string) string {
+	var buffer bytes.Buffer
+
+	options := WriterOptions{Delimiter: ",", VertexColumns: []VertexColumn{
+		{Header: "Label", Name: "label", Strings: map[string]string{"e-1": "1.0", "e-2": "0.\"1\""}},
+		{Header: "Count", Name: "count", Integers: map[string]int{"e-1": 3}},
+	}}
+
+	writer := newVertexWriter(format, &buffer, options)
+	writer.WriteHeader()
+	writer.WriteVertex("e-1", "", 1)
+	writer.WriteVertex("e-2", "", 0)
+	writer.Flush()
+
+	return buffer.String()
+}
+
+func TestVertexWritersColumns(t *testing.T) {
+
+	expected := map[string]string{
+		"vertices": "Entity ID,Component ID,Label,Count\ne-1,1,1.0,3\ne-2,0,\"0.\"\"1\"\"\",0\n",
+		"jsonl": `{"entity_id":"e-1","component_id":1,"label":"1.0","count":3}` + "\n" +
+			`{"entity_id":"e-2","component_id":0,"label":"0.\"1\"","count":0}` + "\n",
+		"json": `[{"component_id":0,"entity_ids":["e-2"],"vertex_columns":{"count":[0],"label":["0.\"1\""]}},` +
+			`{"component_id":1,"entity_ids":["e-1"],"vertex_columns":{"count":[3],"label":["1.0"]}}]` + "\n",
+	}
+
+	for format, expectedOutput := range expected {
+		if actual := writeVerticesWithColumns(format); expectedOutput != actual {
+			t.Fatalf("Expected %q for format %v, got %q\n", expectedOutput, format, actual)
+		}
+	}
+}
+
+func TestJSONVertexWriter(t *testing.T) {
+	actual := writeVertices("json", ",", false)
+	expected := `[{"component_id":0,"entity_ids":["e-2"]},{"component_id":1,"entity_ids":["e-1"]}]` + "\n"
+
+	if expected != actual {
+		t.Fatalf("Expected %q, got %q\n", expected, actual)
+	}
+}
+
+func TestJSONVertexWriterRawIDs(t *testing.T) {
+	actual := writeVertices("json", ",", true)
+	expected := `[{"component_id":0,"entity_ids":["e-2"],"raw_entity_ids":[["E-2","e-02"]]},` +
+		`{"component_id":1,"entity_ids":["e-1"],"raw_entity_ids":[["E-1"]]}]` + "\n"
+
+	if expected != actual {
+		t.Fatalf("Expected %q, got %q\n", expected, actual)
+	}
+}
+
+func TestCalculateConnectedComponentsJSONLines(t *testing.T) {
+
+	// Calculate the connected components and write them as JSON Lines
+	params := NewParameters("./test/test-1/edge_list.csv", "./test/test-1/actual.jsonl", ",")
+	params.OutputFormat = "jsonl"
+	calculateConnectedComponentsWithParameters(params)
+
+	// Read the actual and expected results
+	if !FilesHaveSameContent("./test/test-1/actual.jsonl", "./test/test-1/expected.jsonl") {
+		t.Fatal("Actual results differ from expected results")
+	}
+}